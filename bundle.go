@@ -0,0 +1,189 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type (
+	// BundleOptions configures how DetailBundle traverses the related content of a game.
+	BundleOptions struct {
+		// MaxDepth limits how many levels of related content are followed, starting at the base game.
+		// A depth of 1 only includes the content listed on the base game itself. The default is 1.
+		MaxDepth int
+		// GameTypes restricts which kinds of related content (e.g. "dlc") become part of the bundle.
+		// If empty, all related content is included.
+		GameTypes []string
+	}
+
+	// GameBundleContent is a single piece of related content of a bundle, e.g. a DLC or an expansion.
+	GameBundleContent struct {
+		GameDetailsGameDataRelationships
+		// ParentID is the ID of the game the content was listed on.
+		ParentID int `json:"parent_id"`
+		// Depth is the distance to the base game, starting at 1 for content listed on the base game.
+		Depth int `json:"depth"`
+	}
+
+	// GameBundle contains a base game, all of its related content and the aggregated completion times of both.
+	// All times are in seconds.
+	GameBundle struct {
+		Base     *GameDetails        `json:"base"`
+		Content  []GameBundleContent `json:"content"`
+		CompMain int                 `json:"comp_main"`
+		CompPlus int                 `json:"comp_plus"`
+		Comp100  int                 `json:"comp_100"`
+		CompAll  int                 `json:"comp_all"`
+	}
+
+	// bundleNode is a game whose related content is still to be traversed.
+	bundleNode struct {
+		gameID int
+		depth  int
+	}
+
+	// bundleWalker keeps track of the visited games while resolving a bundle.
+	bundleWalker struct {
+		client  *Client
+		visited map[int]*GameDetails
+	}
+)
+
+// maxParentHops is the maximum number of GameParent links followed to find the base game.
+const maxParentHops = 10
+
+var GameNotFoundErr = errors.New("game not found")
+
+// DetailBundle returns the base game of the given game ID together with all of its related content like DLCs and
+// expansions, and the aggregated completion times of the whole bundle.
+// If gameID points to a DLC, its parent is resolved via GameParent first.
+// BundleOptions is optional, by default only the content listed on the base game is included.
+// Every game's details are fetched at most once per call.
+func (c *Client) DetailBundle(ctx context.Context, gameID int, options *BundleOptions) (*GameBundle, error) {
+	if gameID == 0 {
+		return nil, GameIDRequiredErr
+	}
+
+	if options == nil {
+		options = &BundleOptions{}
+	}
+
+	maxDepth := options.MaxDepth
+	if maxDepth < 1 {
+		maxDepth = 1
+	}
+
+	w := &bundleWalker{
+		client:  c,
+		visited: make(map[int]*GameDetails),
+	}
+
+	base, err := w.resolveBase(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseGame := base.Props.PageProps.Game.Data.Game[0]
+
+	bundle := &GameBundle{
+		Base:     base,
+		CompMain: baseGame.CompMain,
+		CompPlus: baseGame.CompPlus,
+		Comp100:  baseGame.Comp100,
+		CompAll:  baseGame.CompAll,
+	}
+
+	seen := map[int]bool{baseGame.GameID: true}
+	queue := []bundleNode{{gameID: baseGame.GameID}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		details, err := w.detail(ctx, current.gameID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, related := range details.Props.PageProps.Game.Data.Relationships {
+			if seen[related.GameID] || !includesGameType(options.GameTypes, related.GameType) {
+				continue
+			}
+			seen[related.GameID] = true
+
+			content := GameBundleContent{
+				GameDetailsGameDataRelationships: related,
+				ParentID:                         current.gameID,
+				Depth:                            current.depth + 1,
+			}
+
+			bundle.Content = append(bundle.Content, content)
+			bundle.CompMain += related.CompMain
+			bundle.CompPlus += related.CompPlus
+			bundle.Comp100 += related.Comp100
+			bundle.CompAll += related.CompAll
+
+			if content.Depth < maxDepth {
+				queue = append(queue, bundleNode{gameID: related.GameID, depth: content.Depth})
+			}
+		}
+	}
+
+	return bundle, nil
+}
+
+// resolveBase follows the GameParent links of the given game until the base game is reached.
+func (w *bundleWalker) resolveBase(ctx context.Context, gameID int) (*GameDetails, error) {
+	details, err := w.detail(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	for hops := 0; hops < maxParentHops; hops++ {
+		parentID := details.Props.PageProps.Game.Data.Game[0].GameParent
+		if parentID == 0 || w.visited[parentID] != nil {
+			return details, nil
+		}
+
+		if details, err = w.detail(ctx, parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	return details, nil
+}
+
+// detail returns the details of a game, fetching them only if the game has not been visited yet.
+func (w *bundleWalker) detail(ctx context.Context, gameID int) (*GameDetails, error) {
+	if details, ok := w.visited[gameID]; ok {
+		return details, nil
+	}
+
+	details, err := w.client.Detail(ctx, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("game %d: %w", gameID, err)
+	}
+
+	if len(details.Props.PageProps.Game.Data.Game) == 0 {
+		return nil, fmt.Errorf("game %d: %w", gameID, GameNotFoundErr)
+	}
+
+	w.visited[gameID] = details
+
+	return details, nil
+}
+
+func includesGameType(gameTypes []string, gameType string) bool {
+	if len(gameTypes) == 0 {
+		return true
+	}
+
+	for _, t := range gameTypes {
+		if t == gameType {
+			return true
+		}
+	}
+
+	return false
+}
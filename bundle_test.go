@@ -0,0 +1,108 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newBundleMockServer(t *testing.T) *mockServer {
+	t.Helper()
+
+	base := mockGame{game: GameDetailsGameDataGame{GameID: 1, GameName: "Base Game", GameType: "game", CompMain: 3600, CompPlus: 7200, Comp100: 10800, CompAll: 5400}}
+	dlc := mockGame{game: GameDetailsGameDataGame{GameID: 2, GameName: "Base Game: DLC", GameType: "dlc", GameParent: 1, CompMain: 600, CompPlus: 1200, Comp100: 1800, CompAll: 900}}
+	expansion := mockGame{game: GameDetailsGameDataGame{GameID: 3, GameName: "Base Game: Expansion", GameType: "expansion", GameParent: 1, CompMain: 1800, CompPlus: 2400, Comp100: 3000, CompAll: 2000}}
+	nested := mockGame{game: GameDetailsGameDataGame{GameID: 4, GameName: "Base Game: Expansion Pack", GameType: "dlc", GameParent: 3, CompMain: 300, CompPlus: 300, Comp100: 300, CompAll: 300}}
+
+	base.relationships = []GameDetailsGameDataRelationships{dlc.relationship(), expansion.relationship()}
+	dlc.relationships = []GameDetailsGameDataRelationships{base.relationship(), expansion.relationship()}
+	expansion.relationships = []GameDetailsGameDataRelationships{base.relationship(), dlc.relationship(), nested.relationship()}
+
+	return newMockServer(t, base, dlc, expansion, nested)
+}
+
+func Test_DetailBundle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newBundleMockServer(t)
+	mockClient := server.client(t)
+
+	bundle, err := mockClient.DetailBundle(ctx, 2, nil)
+	if err != nil {
+		t.Fatalf("DetailBundle() error = %v", err)
+	}
+
+	if got := bundle.Base.Props.PageProps.Game.Data.Game[0].GameID; got != 1 {
+		t.Fatalf("DetailBundle() base = %d, want %d", got, 1)
+	}
+
+	if len(bundle.Content) != 2 {
+		t.Fatalf("DetailBundle() content = %v, want 2 entries", bundle.Content)
+	}
+
+	if bundle.CompMain != 3600+600+1800 || bundle.Comp100 != 10800+1800+3000 {
+		t.Errorf("DetailBundle() comp_main = %d, comp_100 = %d", bundle.CompMain, bundle.Comp100)
+	}
+
+	// The requested DLC and its parent are fetched, every other game is already known from the base game.
+	if calls := server.detailCalls.Load(); calls != 2 {
+		t.Errorf("DetailBundle() fetched %d details, want %d", calls, 2)
+	}
+}
+
+func Test_DetailBundle_MaxDepth(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newBundleMockServer(t)
+	mockClient := server.client(t)
+
+	bundle, err := mockClient.DetailBundle(ctx, 1, &BundleOptions{MaxDepth: 2})
+	if err != nil {
+		t.Fatalf("DetailBundle() error = %v", err)
+	}
+
+	if len(bundle.Content) != 3 {
+		t.Fatalf("DetailBundle() content = %v, want 3 entries", bundle.Content)
+	}
+
+	nested := bundle.Content[2]
+	if nested.GameID != 4 || nested.ParentID != 3 || nested.Depth != 2 {
+		t.Errorf("DetailBundle() nested content = %+v", nested)
+	}
+
+	if bundle.CompMain != 3600+600+1800+300 {
+		t.Errorf("DetailBundle() comp_main = %d, want %d", bundle.CompMain, 3600+600+1800+300)
+	}
+
+	if calls := server.detailCalls.Load(); calls != 3 {
+		t.Errorf("DetailBundle() fetched %d details, want %d", calls, 3)
+	}
+}
+
+func Test_DetailBundle_GameTypes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mockClient := newBundleMockServer(t).client(t)
+
+	bundle, err := mockClient.DetailBundle(ctx, 1, &BundleOptions{GameTypes: []string{"dlc"}})
+	if err != nil {
+		t.Fatalf("DetailBundle() error = %v", err)
+	}
+
+	if len(bundle.Content) != 1 || bundle.Content[0].GameID != 2 {
+		t.Fatalf("DetailBundle() content = %v, want only game 2", bundle.Content)
+	}
+}
+
+func Test_DetailBundle_InvalidGameID(t *testing.T) {
+	mockClient := &Client{}
+
+	_, err := mockClient.DetailBundle(context.Background(), 0, nil)
+	if !errors.Is(err, GameIDRequiredErr) {
+		t.Fatalf(`DetailBundle() expected "%v" error, but received: %v`, GameIDRequiredErr, err)
+	}
+}
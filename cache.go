@@ -0,0 +1,231 @@
+package howlongtobeat
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// resultCache is an in-memory LRU cache for search and detail results with a fixed time to live.
+	resultCache struct {
+		mu      sync.Mutex
+		ttl     time.Duration
+		size    int
+		order   *list.List
+		entries map[string]*list.Element
+
+		// refreshAfter is the soft time to live, after which results are refreshed in the background.
+		refreshAfter time.Duration
+		// maxStale is the time expired results are kept to be served when HowLongToBeat fails.
+		maxStale time.Duration
+		// refreshing contains the keys with a running background refresh.
+		refreshing map[string]bool
+	}
+
+	cacheEntry struct {
+		key    string
+		value  any
+		stored time.Time
+	}
+
+	// StaleOptions configures serving stale results from the cache, see WithStaleWhileRevalidate.
+	StaleOptions struct {
+		// RefreshAfter is the soft time to live of cached results. Older results are returned as stale and refreshed
+		// in the background. It should be shorter than the time to live of WithCache, 0 disables background refreshes.
+		RefreshAfter time.Duration
+		// MaxStale is the time results are kept after their time to live expired, to be returned as stale if
+		// HowLongToBeat fails or the circuit breaker is open. The default is 24 hours.
+		MaxStale time.Duration
+	}
+
+	// cacheState is the state of a cached result.
+	cacheState int
+)
+
+const (
+	cacheMiss cacheState = iota
+	cacheFresh
+	// cacheRefresh is a result older than the soft time to live, which is served while it is refreshed.
+	cacheRefresh
+	// cacheExpired is a result older than the time to live, which is only served if HowLongToBeat fails.
+	cacheExpired
+)
+
+const (
+	// defaultCacheSize is the number of cached results if WithCache is used without a size.
+	defaultCacheSize = 1000
+	// defaultMaxStale is the time expired results are kept if WithStaleWhileRevalidate is used without MaxStale.
+	defaultMaxStale = 24 * time.Hour
+)
+
+// WithCache caches the results of Search and Detail in memory for the given duration.
+// At most size results are kept, the least recently used results are evicted first. If size is 0, up to 1000 results
+// are cached. Cached results are shared between callers and must not be modified.
+func WithCache(ttl time.Duration, size int) Option {
+	return func(client *Client) {
+		if ttl <= 0 {
+			return
+		}
+
+		if size <= 0 {
+			size = defaultCacheSize
+		}
+
+		client.cache = &resultCache{
+			ttl:     ttl,
+			size:    size,
+			order:   list.New(),
+			entries: make(map[string]*list.Element),
+		}
+	}
+}
+
+// WithStaleWhileRevalidate keeps results cached with WithCache after they expired and returns them if HowLongToBeat
+// fails, including a CircuitOpenError. With RefreshAfter, results are refreshed in the background once they are older
+// than RefreshAfter, while the cached result is returned right away. Results returned this way have Stale set, Age is
+// set for every cached result. StaleOptions is optional. Without WithCache, the option has no effect.
+func WithStaleWhileRevalidate(options *StaleOptions) Option {
+	return func(client *Client) {
+		client.staleOptions = &StaleOptions{MaxStale: defaultMaxStale}
+
+		if options != nil && options.RefreshAfter > 0 {
+			client.staleOptions.RefreshAfter = options.RefreshAfter
+		}
+
+		if options != nil && options.MaxStale > 0 {
+			client.staleOptions.MaxStale = options.MaxStale
+		}
+	}
+}
+
+// get returns the cached value of the key along with its age and state. Results are dropped once they are older than
+// the time to live and, if set, maxStale.
+func (r *resultCache) get(key string) (any, time.Duration, cacheState) {
+	if r == nil {
+		return nil, 0, cacheMiss
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	elem, ok := r.entries[key]
+	if !ok {
+		return nil, 0, cacheMiss
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	age := time.Since(entry.stored)
+
+	if age > r.ttl+r.maxStale {
+		r.order.Remove(elem)
+		delete(r.entries, key)
+		return nil, 0, cacheMiss
+	}
+
+	r.order.MoveToFront(elem)
+
+	switch {
+	case age > r.ttl:
+		return entry.value, age, cacheExpired
+	case r.refreshAfter > 0 && age > r.refreshAfter:
+		return entry.value, age, cacheRefresh
+	default:
+		return entry.value, age, cacheFresh
+	}
+}
+
+// startRefresh reports whether a background refresh of the key may start. Only one refresh per key runs at a time.
+func (r *resultCache) startRefresh(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.refreshing[key] {
+		return false
+	}
+
+	if r.refreshing == nil {
+		r.refreshing = make(map[string]bool)
+	}
+
+	r.refreshing[key] = true
+
+	return true
+}
+
+func (r *resultCache) endRefresh(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.refreshing, key)
+}
+
+// revalidate refreshes a cached result in the background with fetch, unless a refresh of the key is already running.
+// The refresh is observed as a separate operation and is not canceled with ctx.
+func (c *Client) revalidate(ctx context.Context, op Operation, key string, info OperationInfo, fetch func(ctx context.Context, info *OperationInfo) error) {
+	if !c.cache.startRefresh(key) {
+		return
+	}
+
+	go func() {
+		defer c.cache.endRefresh(key)
+
+		ctx, obs := c.observe(context.WithoutCancel(ctx), op)
+
+		info.Err = fetch(ctx, &info)
+		obs.end(info)
+
+		if info.Err != nil {
+			c.log(ctx, slog.LevelWarn, "background refresh failed", slog.String("key", key), slog.Any("error", info.Err))
+		}
+	}()
+}
+
+// serveStale reports whether an expired result is returned instead of the error of the request. Errors of the caller,
+// e.g. a canceled context, are returned as is.
+func (c *Client) serveStale(ctx context.Context, state cacheState, key string, age time.Duration, err error) bool {
+	if state != cacheExpired || ctx.Err() != nil {
+		return false
+	}
+
+	c.log(ctx, slog.LevelWarn, "serving stale result", slog.String("key", key), slog.Duration("age", age), slog.Any("error", err))
+
+	return true
+}
+
+func (r *resultCache) set(key string, value any) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if elem, ok := r.entries[key]; ok {
+		elem.Value = &cacheEntry{key: key, value: value, stored: time.Now()}
+		r.order.MoveToFront(elem)
+		return
+	}
+
+	r.entries[key] = r.order.PushFront(&cacheEntry{key: key, value: value, stored: time.Now()})
+
+	for r.order.Len() > r.size {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func searchCacheKey(searchTerm string, searchModifier SearchModifier, request *searchRequest) string {
+	term := strings.ToLower(strings.Join(strings.Fields(searchTerm), " "))
+
+	return fmt.Sprintf("search:%s:%s:%d:%d", term, searchModifier, request.SearchPage, request.Size)
+}
+
+func detailCacheKey(gameID int) string {
+	return fmt.Sprintf("detail:%d", gameID)
+}
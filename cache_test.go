@@ -0,0 +1,146 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_resultCache(t *testing.T) {
+	mockClient, err := New(WithCache(time.Minute, 2))
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	cache := mockClient.cache
+	cache.set("a", 1)
+	cache.set("b", 2)
+
+	// Reading "a" makes "b" the least recently used entry.
+	if v, _, state := cache.get("a"); state != cacheFresh || v != 1 {
+		t.Fatalf("get() = %v, %v, want 1, %v", v, state, cacheFresh)
+	}
+
+	cache.set("c", 3)
+
+	if _, _, state := cache.get("b"); state != cacheMiss {
+		t.Errorf("get() returned evicted entry")
+	}
+
+	if _, _, state := cache.get("c"); state != cacheFresh {
+		t.Errorf("get() did not return the newest entry")
+	}
+}
+
+func Test_resultCache_Expired(t *testing.T) {
+
+	mockClient, _ := New(WithCache(time.Millisecond, 0))
+	mockClient.cache.set("a", 1)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, _, state := mockClient.cache.get("a"); state != cacheMiss {
+		t.Errorf("get() returned expired entry")
+	}
+}
+
+func Test_WithCache_SearchAndDetail(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt"}})
+	mockClient := server.client(t, WithCache(time.Minute, 10))
+
+	for i := 0; i < 3; i++ {
+		if _, err := mockClient.Search(ctx, "The  Witcher 3", SearchModifierNone, nil); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+
+		if _, err := mockClient.Detail(ctx, 10270); err != nil {
+			t.Fatalf("Detail() error = %v", err)
+		}
+	}
+
+	// Differently spaced search terms share the same cache entry.
+	if _, err := mockClient.Search(ctx, "the witcher 3", SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if calls := server.searchCalls.Load(); calls != 1 {
+		t.Errorf("Search() hit the server %d times, want %d", calls, 1)
+	}
+
+	if calls := server.detailCalls.Load(); calls != 1 {
+		t.Errorf("Detail() hit the server %d times, want %d", calls, 1)
+	}
+}
+
+func Test_WithStaleWhileRevalidate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt"}})
+	mockClient := server.client(t, WithStaleWhileRevalidate(&StaleOptions{RefreshAfter: 20 * time.Millisecond}), WithCache(time.Minute, 10))
+
+	if details, err := mockClient.Detail(ctx, 10270); err != nil || details.Stale || details.Age != 0 {
+		t.Fatalf("Detail() = %+v, %v, want a fresh result", details, err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	details, err := mockClient.Detail(ctx, 10270)
+	if err != nil || !details.Stale || details.Age < 30*time.Millisecond {
+		t.Fatalf("Detail() stale = %v, age = %v, error = %v, want a stale result", details.Stale, details.Age, err)
+	}
+
+	// The result past the soft time to live is refreshed in the background.
+	deadline := time.Now().Add(time.Second)
+	for server.detailCalls.Load() != 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if calls := server.detailCalls.Load(); calls != 2 {
+		t.Fatalf("Detail() hit the server %d times, want a background refresh", calls)
+	}
+
+	if details, err = mockClient.Detail(ctx, 10270); err != nil || details.Stale {
+		t.Errorf("Detail() after refresh stale = %v, error = %v", details.Stale, err)
+	}
+}
+
+func Test_WithStaleWhileRevalidate_ServeOnError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t)
+	mockClient := server.client(t, WithCache(10*time.Millisecond, 10), WithStaleWhileRevalidate(nil), WithCircuitBreaker(&CircuitBreakerOptions{ConsecutiveFailures: 1, Cooldown: time.Minute}))
+
+	if _, err := mockClient.Search(ctx, "witcher", SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	// The first failure opens the circuit, the second request is not sent. Both return the expired result.
+	for i := 0; i < 2; i++ {
+		result, err := mockClient.Search(ctx, "witcher", SearchModifierNone, nil)
+		if err != nil || !result.Stale || result.Age < 20*time.Millisecond {
+			t.Fatalf("Search() = %+v, %v, want the stale result", result, err)
+		}
+	}
+
+	if calls := server.searchCalls.Load(); calls != 2 || mockClient.CircuitState() != CircuitOpen {
+		t.Errorf("Search() hit the server %d times, circuit %v", calls, mockClient.CircuitState())
+	}
+
+	// Without a cached result, the error is returned.
+	if _, err := mockClient.Search(ctx, "zelda", SearchModifierNone, nil); !errors.Is(err, CircuitOpenErr) {
+		t.Errorf(`Search() expected "%v" error, but received: %v`, CircuitOpenErr, err)
+	}
+}
@@ -0,0 +1,348 @@
+// Package cassette provides an http.RoundTripper that records HowLongToBeat traffic to a file and replays it later,
+// so tests can run deterministically without network access.
+//
+// Requests are matched on their method, path and body. JSON bodies are normalized before matching, so the order of
+// keys does not matter. Query parameters are ignored, since the token request carries a timestamp.
+// Auth tokens are redacted before the traffic is written to disk.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	// Mode is the mode of a Recorder.
+	Mode int
+
+	// Recorder is an http.RoundTripper that records or replays HTTP interactions.
+	// Use it with howlongtobeat.WithHTTPClient(recorder.Client()).
+	Recorder struct {
+		path      string
+		mode      Mode
+		transport http.RoundTripper
+
+		mu           sync.Mutex
+		interactions []Interaction
+		replayed     []bool
+	}
+
+	// Interaction is a single recorded request and its response.
+	Interaction struct {
+		Request  Request  `json:"request"`
+		Response Response `json:"response"`
+	}
+
+	// Request is a recorded HTTP request.
+	Request struct {
+		Method  string      `json:"method"`
+		URL     string      `json:"url"`
+		Headers http.Header `json:"headers,omitempty"`
+		Body    string      `json:"body,omitempty"`
+	}
+
+	// Response is a recorded HTTP response.
+	Response struct {
+		StatusCode int         `json:"status_code"`
+		Headers    http.Header `json:"headers,omitempty"`
+		Body       string      `json:"body,omitempty"`
+	}
+
+	// UnmatchedRequestError is returned in replay mode for requests without a recorded interaction.
+	UnmatchedRequestError struct {
+		Method string
+		URL    string
+		Body   string
+	}
+
+	cassetteFile struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+)
+
+const (
+	// ModeReplay serves responses from the cassette file and never touches the network.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the network and records them. Call Save to write the cassette file.
+	ModeRecord
+)
+
+// Redacted replaces auth tokens in recorded interactions.
+const Redacted = "REDACTED"
+
+// redactedHeaders are the headers whose values are never written to a cassette.
+var redactedHeaders = []string{"X-Auth-Token", "Cookie", "Set-Cookie", "Authorization"}
+
+var CassetteNotFoundErr = errors.New("cassette not found")
+
+func (e *UnmatchedRequestError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("cassette: no recorded interaction for %s %s", e.Method, e.URL)
+	}
+
+	return fmt.Sprintf("cassette: no recorded interaction for %s %s with body %s", e.Method, e.URL, e.Body)
+}
+
+// New creates a new Recorder for the cassette file at path.
+// In replay mode the file is loaded immediately, CassetteNotFoundErr is returned if it does not exist.
+// In record mode requests are sent with transport, or http.DefaultTransport if transport is nil.
+func New(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: transport,
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", CassetteNotFoundErr, path)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var file cassetteFile
+		if err = json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+		}
+
+		r.interactions = file.Interactions
+		r.replayed = make([]bool, len(file.Interactions))
+	}
+
+	return r, nil
+}
+
+// Client returns an http.Client using the Recorder as transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+
+	return r.record(req, body)
+}
+
+// Save writes all recorded interactions to the cassette file. It is a no-op in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode == ModeReplay {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(cassetteFile{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: redactHeaders(req.Header),
+			Body:    string(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    redactHeaders(resp.Header),
+			Body:       redactBody(respBody),
+		},
+	}
+	interaction.Response.Headers.Del("Content-Length")
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	// The caller receives the original, unredacted response.
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := matchKey(req.Method, req.URL.Path, body)
+
+	// Prefer interactions that have not been replayed yet, so repeated requests replay the recorded sequence.
+	match := -1
+	for i, interaction := range r.interactions {
+		if recordedKey(interaction.Request) != key {
+			continue
+		}
+
+		if !r.replayed[i] {
+			match = i
+			break
+		}
+
+		if match == -1 {
+			match = i
+		}
+	}
+
+	if match == -1 {
+		return nil, &UnmatchedRequestError{Method: req.Method, URL: req.URL.String(), Body: string(body)}
+	}
+
+	r.replayed[match] = true
+	recorded := r.interactions[match].Response
+
+	header := recorded.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set("Content-Length", strconv.Itoa(len(recorded.Body)))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readBody reads the request body and replaces it, so it can still be sent.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+func recordedKey(req Request) string {
+	path := req.URL
+	if i := strings.Index(path, "://"); i != -1 {
+		path = path[i+3:]
+		if j := strings.IndexByte(path, '/'); j != -1 {
+			path = path[j:]
+		} else {
+			path = "/"
+		}
+	}
+
+	if i := strings.IndexByte(path, '?'); i != -1 {
+		path = path[:i]
+	}
+
+	return matchKey(req.Method, path, []byte(req.Body))
+}
+
+func matchKey(method, path string, body []byte) string {
+	if path == "" {
+		path = "/"
+	}
+
+	return method + " " + path + " " + normalizeBody(body)
+}
+
+// normalizeBody re-encodes JSON bodies with sorted keys and without insignificant whitespace.
+func normalizeBody(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(normalized)
+}
+
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+
+	for _, name := range redactedHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, Redacted)
+		}
+	}
+
+	return redacted
+}
+
+// redactBody redacts the token of token responses.
+func redactBody(body []byte) string {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return string(body)
+	}
+
+	if _, ok := object["token"]; !ok {
+		return string(body)
+	}
+
+	object["token"] = json.RawMessage(strconv.Quote(Redacted))
+
+	redacted, err := json.Marshal(object)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redacted)
+}
@@ -0,0 +1,162 @@
+package cassette_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+	"github.com/forbiddencoding/howlongtobeat/cassette"
+	"github.com/forbiddencoding/howlongtobeat/hltbtest"
+)
+
+// record captures a search and a detail request against a fake HLTB and returns the cassette path and base URL.
+func record(t *testing.T) (string, string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := hltbtest.NewServer()
+	t.Cleanup(server.Close)
+	server.AddGame(howlongtobeat.GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt", GameType: "game", CompMain: 185696})
+
+	path := filepath.Join(t.TempDir(), "witcher.json")
+
+	recorder, err := cassette.New(path, cassette.ModeRecord, server.Client().Transport)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	client, err := howlongtobeat.New(howlongtobeat.WithHTTPClient(recorder.Client()), howlongtobeat.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("howlongtobeat.New() error = %v", err)
+	}
+
+	if _, err = client.Search(ctx, "Witcher 3", howlongtobeat.SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if _, err = client.Detail(ctx, 10270); err != nil {
+		t.Fatalf("Detail() error = %v", err)
+	}
+
+	if err = recorder.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	return path, server.URL
+}
+
+func replayClient(t *testing.T, path, baseURL string) *howlongtobeat.Client {
+	t.Helper()
+
+	recorder, err := cassette.New(path, cassette.ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	client, err := howlongtobeat.New(howlongtobeat.WithHTTPClient(recorder.Client()), howlongtobeat.WithBaseURL(baseURL))
+	if err != nil {
+		t.Fatalf("howlongtobeat.New() error = %v", err)
+	}
+
+	return client
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	path, baseURL := record(t)
+	client := replayClient(t, path, baseURL)
+
+	result, err := client.Search(ctx, "Witcher 3", howlongtobeat.SearchModifierNone, nil)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if len(result.Data) != 1 || result.Data[0].GameID != 10270 {
+		t.Fatalf("Search() = %+v", result.Data)
+	}
+
+	details, err := client.Detail(ctx, 10270)
+	if err != nil {
+		t.Fatalf("Detail() error = %v", err)
+	}
+
+	if got := details.Props.PageProps.Game.Data.Game[0].CompMain; got != 185696 {
+		t.Errorf("Detail() comp_main = %d, want %d", got, 185696)
+	}
+}
+
+func TestRecorder_Redacts(t *testing.T) {
+	path, _ := record(t)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	if strings.Contains(string(data), hltbtest.Token) {
+		t.Fatalf("cassette contains the auth token:\n%s", data)
+	}
+
+	if !strings.Contains(string(data), cassette.Redacted) {
+		t.Errorf("cassette does not contain %q", cassette.Redacted)
+	}
+}
+
+func TestRecorder_Unmatched(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	path, baseURL := record(t)
+	client := replayClient(t, path, baseURL)
+
+	_, err := client.Search(ctx, "Elden Ring", howlongtobeat.SearchModifierNone, nil)
+
+	var unmatched *cassette.UnmatchedRequestError
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("Search() error = %v, want UnmatchedRequestError", err)
+	}
+
+	if unmatched.Method != http.MethodPost || !strings.Contains(unmatched.Body, "Elden") {
+		t.Errorf("UnmatchedRequestError = %+v", unmatched)
+	}
+}
+
+func TestRecorder_NormalizedBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "body.json")
+	data := `{"interactions":[{"request":{"method":"POST","url":"https://example.com/api/finder","body":"{\"b\":1,\"a\":[1,2]}"},"response":{"status_code":200,"body":"ok"}}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	recorder, err := cassette.New(path, cassette.ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	resp, err := recorder.Client().Post("http://localhost/api/finder?t=1", "application/json", strings.NewReader(`{ "a": [1, 2], "b": 1 }`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Post() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestNew_MissingCassette(t *testing.T) {
+	_, err := cassette.New(filepath.Join(t.TempDir(), "missing.json"), cassette.ModeReplay, nil)
+	if !errors.Is(err, cassette.CassetteNotFoundErr) {
+		t.Fatalf("New() error = %v, want %v", err, cassette.CassetteNotFoundErr)
+	}
+}
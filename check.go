@@ -0,0 +1,206 @@
+package howlongtobeat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type (
+	// CheckStatus is the outcome of a step of Client.Check.
+	CheckStatus string
+
+	// CheckReport is the result of Client.Check.
+	CheckReport struct {
+		// OK is set if all steps passed.
+		OK       bool          `json:"ok"`
+		Duration time.Duration `json:"duration"`
+		Steps    []CheckStep   `json:"steps"`
+	}
+
+	// CheckStep is the result of a step of Client.Check. The steps are OperationToken, OperationSearch and
+	// OperationDetail.
+	CheckStep struct {
+		Name    Operation     `json:"name"`
+		Status  CheckStatus   `json:"status"`
+		Latency time.Duration `json:"latency"`
+		// StatusCode is the status code of the response, if one was received.
+		StatusCode int `json:"status_code,omitempty"`
+		// Error is the message of Err.
+		Error string `json:"error,omitempty"`
+		Err   error  `json:"-"`
+		// Drift lists the differences between the payload and the structs it is decoded into. Drift alone does not
+		// fail a step.
+		Drift *SchemaDrift `json:"drift,omitempty"`
+	}
+)
+
+const (
+	CheckPassed  CheckStatus = "passed"
+	CheckFailed  CheckStatus = "failed"
+	CheckSkipped CheckStatus = "skipped"
+)
+
+const (
+	// checkSearchTerm and checkGameID are the search and the game page requested by Client.Check.
+	checkSearchTerm = "The Witcher 3: Wild Hunt"
+	checkGameID     = 10270
+)
+
+var UnexpectedShapeErr = errors.New("unexpected response shape")
+
+// Check verifies that HowLongToBeat is reachable and still matches the assumptions of the library, e.g. in a deploy
+// pipeline. It fetches a new token, searches for a known game with it, fetches the page of a known game and validates
+// its __NEXT_DATA__ document. The cache is bypassed and the token of the Client is left untouched.
+// The report contains the latency and status of every step, the error is set if any step failed.
+func (c *Client) Check(ctx context.Context) (*CheckReport, error) {
+	start := time.Now()
+	report := &CheckReport{}
+
+	var apiData *ApiData
+
+	token := c.checkStep(OperationToken, func() (*SchemaDrift, error) {
+		var err error
+		apiData, err = c.checkToken(ctx)
+		return nil, err
+	})
+
+	search := CheckStep{Name: OperationSearch, Status: CheckSkipped, Error: "no token"}
+	if token.Status == CheckPassed {
+		search = c.checkStep(OperationSearch, func() (*SchemaDrift, error) {
+			return c.checkSearch(ctx, apiData)
+		})
+	}
+
+	detail := c.checkStep(OperationDetail, func() (*SchemaDrift, error) {
+		return c.checkDetail(ctx)
+	})
+
+	report.Steps = []CheckStep{token, search, detail}
+	report.Duration = time.Since(start)
+	report.OK = true
+
+	var errs []error
+	for _, step := range report.Steps {
+		if step.Status != CheckPassed {
+			report.OK = false
+		}
+
+		if step.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, step.Err))
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// checkStep runs a step of Check and measures its latency.
+func (c *Client) checkStep(name Operation, run func() (*SchemaDrift, error)) CheckStep {
+	start := time.Now()
+
+	drift, err := run()
+
+	step := CheckStep{Name: name, Status: CheckPassed, Latency: time.Since(start), StatusCode: statusCodeOf(err), Err: err}
+	if drift != nil && drift.HasDrift() {
+		step.Drift = drift
+	}
+
+	if err != nil {
+		step.Status = CheckFailed
+		step.Error = err.Error()
+	} else if step.StatusCode == 0 {
+		step.StatusCode = http.StatusOK
+	}
+
+	return step
+}
+
+// checkToken fetches new api data without replacing the api data of the Client. apiMu is not held during the
+// requests, so concurrent calls are not blocked by a slow check.
+func (c *Client) checkToken(ctx context.Context) (*ApiData, error) {
+	c.apiMu.Lock()
+	discover := c.discoverEndpoint
+	c.apiMu.Unlock()
+
+	apiData := &ApiData{}
+
+	if err := c.requestToken(ctx, apiData, false); err != nil {
+		return nil, err
+	}
+
+	if !discover {
+		apiData.endpointPath = hltbSearchEndpoint
+		return apiData, nil
+	}
+
+	if err := c.discoverEndpointPath(ctx, apiData); err != nil {
+		return nil, err
+	}
+
+	return apiData, nil
+}
+
+func (c *Client) checkSearch(ctx context.Context, apiData *ApiData) (*SchemaDrift, error) {
+	body, err := json.Marshal(c.prepSearchRequest(checkSearchTerm, SearchModifierNone, &SearchGamePagination{Page: 1, PageSize: 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.searchHTTPRequest(ctx, body, apiData.endpointPath, apiData.token)
+	if err != nil {
+		return nil, fmt.Errorf("create search request: %w", err)
+	}
+
+	var payload json.RawMessage
+	if err = c.do(req, c.jsonParser(&payload)); err != nil {
+		return nil, err
+	}
+
+	var result SearchGame
+	if err = json.Unmarshal(payload, &result); err != nil {
+		return nil, fmt.Errorf("%w: %w", UnexpectedShapeErr, err)
+	}
+
+	if len(result.Data) == 0 || result.Data[0].GameID == 0 || result.Data[0].GameName == "" {
+		return nil, fmt.Errorf("%w: no game found for %q", UnexpectedShapeErr, checkSearchTerm)
+	}
+
+	return DiffSchema(PayloadSearch, payload)
+}
+
+func (c *Client) checkDetail(ctx context.Context) (*SchemaDrift, error) {
+	req, err := c.detailHTTPRequest(ctx, checkGameID)
+	if err != nil {
+		return nil, fmt.Errorf("create game details request: %w", err)
+	}
+
+	var document []byte
+
+	parser := func(resp *http.Response) (err error) {
+		document, err = readNextData(resp.Body)
+		return err
+	}
+
+	if err = c.do(req, parser); err != nil {
+		return nil, err
+	}
+
+	var response gameDetailsResponse
+	if err = json.Unmarshal(document, &response); err != nil {
+		return nil, fmt.Errorf("%w: %w", UnexpectedShapeErr, err)
+	}
+
+	details, err := response.convertResponseToGameDetails()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", UnexpectedShapeErr, err)
+	}
+
+	if game := details.Props.PageProps.Game.Data.Game; len(game) == 0 || game[0].GameID != checkGameID || game[0].GameName == "" {
+		return nil, fmt.Errorf("%w: game %d not found in __NEXT_DATA__", UnexpectedShapeErr, checkGameID)
+	}
+
+	return DiffSchema(PayloadDetail, document)
+}
@@ -0,0 +1,126 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Check(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: checkGameID, GameName: "The Witcher 3: Wild Hunt"}})
+	mockClient := server.client(t, WithCache(time.Minute, 10))
+
+	report, err := mockClient.Check(ctx)
+	if err != nil || !report.OK || len(report.Steps) != 3 {
+		t.Fatalf("Check() = %+v, %v, want all steps passed", report, err)
+	}
+
+	for i, name := range []Operation{OperationToken, OperationSearch, OperationDetail} {
+		step := report.Steps[i]
+		if step.Name != name || step.Status != CheckPassed || step.StatusCode != http.StatusOK || step.Latency <= 0 {
+			t.Errorf("Check() step %d = %+v, want %s passed", i, step, name)
+		}
+	}
+
+	// The check bypasses the cache and does not replace the token of the client.
+	if _, err = mockClient.Check(ctx); err != nil || server.detailCalls.Load() != 2 || mockClient.apiData != nil {
+		t.Errorf("Check() error = %v, detail calls = %d, api data = %v", err, server.detailCalls.Load(), mockClient.apiData)
+	}
+}
+
+func Test_Check_Failures(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 1, GameName: "Unrelated"}})
+	mockClient := server.client(t)
+
+	report, err := mockClient.Check(ctx)
+	if report.OK || !errors.Is(err, UnexpectedShapeErr) {
+		t.Fatalf(`Check() expected "%v" error, but received: %v`, UnexpectedShapeErr, err)
+	}
+
+	if search := report.Steps[1]; search.Status != CheckFailed || !errors.Is(search.Err, UnexpectedShapeErr) {
+		t.Errorf("Check() search step = %+v, want a shape error", search)
+	}
+
+	if detail := report.Steps[2]; detail.Status != CheckFailed || detail.StatusCode != http.StatusNotFound {
+		t.Errorf("Check() detail step = %+v, want status %d", detail, http.StatusNotFound)
+	}
+
+	// Without a token, the search is skipped.
+	server.Close()
+
+	if report, _ = mockClient.Check(ctx); report.Steps[0].Status != CheckFailed || report.Steps[1].Status != CheckSkipped {
+		t.Errorf("Check() steps = %+v, want a failed token and a skipped search", report.Steps)
+	}
+}
+
+func Test_Check_Concurrent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: checkGameID, GameName: "The Witcher 3: Wild Hunt"}})
+
+	var (
+		block   atomic.Bool
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+
+	mockClient := server.client(t, WithMiddleware(Middleware{BeforeRequest: func(op Operation, req *http.Request) error {
+		if op == OperationToken && block.Load() {
+			close(started)
+			<-release
+		}
+		return nil
+	}}))
+
+	if _, err := mockClient.Search(ctx, "Witcher", SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	block.Store(true)
+	mockClient.tokenRejected = true
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := mockClient.Check(ctx)
+		done <- err
+	}()
+
+	<-started
+
+	// A search with the token of the client does not wait for the token request of the check.
+	searched := make(chan error, 1)
+	go func() {
+		_, err := mockClient.Search(ctx, "Witcher 3", SearchModifierNone, nil)
+		searched <- err
+	}()
+
+	select {
+	case err := <-searched:
+		if err != nil {
+			t.Errorf("Search() during Check() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Search() blocked by Check()")
+	}
+
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	// The next token of the client is still reported as a refresh.
+	if !mockClient.tokenRejected {
+		t.Errorf("Check() reset the rejected token of the client")
+	}
+}
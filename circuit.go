@@ -0,0 +1,335 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// CircuitState is the state of the circuit breaker.
+	CircuitState int
+
+	// CircuitBreakerOptions configures the circuit breaker. The zero value opens the circuit after 5 consecutive
+	// failures, keeps it open for 30 seconds and then lets a single request through to probe HowLongToBeat.
+	CircuitBreakerOptions struct {
+		// ConsecutiveFailures opens the circuit after this many failed requests in a row.
+		ConsecutiveFailures int
+		// FailureRate opens the circuit once this share of the last Window requests failed, e.g. 0.5.
+		// A rate of 0 disables the check.
+		FailureRate float64
+		// Window is the number of recent requests the failure rate is computed over. The default is 20.
+		Window int
+		// Cooldown is the time the circuit stays open before probing HowLongToBeat again.
+		Cooldown time.Duration
+		// HalfOpenRequests is the number of probe requests in the half-open state. The circuit closes once all of
+		// them succeeded and opens again on the first failure.
+		HalfOpenRequests int
+		// OnStateChange is called on every state change. It must not block.
+		OnStateChange func(from, to CircuitState)
+	}
+
+	// CircuitOpenError is returned without sending the request while the circuit breaker is open.
+	CircuitOpenError struct {
+		// RetryAfter is the remaining time until the circuit breaker lets requests through again. While half-open,
+		// it is the cooldown, as a failing probe opens the circuit for that long.
+		RetryAfter time.Duration
+	}
+
+	circuitBreaker struct {
+		mu      sync.Mutex
+		options CircuitBreakerOptions
+
+		state     CircuitState
+		openUntil time.Time
+		// failures is the number of consecutive failures while closed.
+		failures int
+		// outcomes is a ring buffer of the last Window outcomes, true for failures.
+		outcomes []bool
+		next     int
+		// probes is the number of probe requests in flight, successes the number of succeeded probes while half-open.
+		probes    int
+		successes int
+		// generation is incremented on every state change, so outcomes of requests let through in an earlier state
+		// are dropped.
+		generation int
+	}
+
+	// circuitOutcome is the outcome of a request as seen by the circuit breaker.
+	circuitOutcome int
+)
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails all requests with a CircuitOpenError until the cooldown elapsed.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through.
+	CircuitHalfOpen
+)
+
+const (
+	circuitSuccess circuitOutcome = iota
+	circuitFailure
+	// circuitIgnored is the outcome of requests canceled by the caller.
+	circuitIgnored
+)
+
+const (
+	defaultCircuitFailures  = 5
+	defaultCircuitWindow    = 20
+	defaultCircuitCooldown  = 30 * time.Second
+	defaultCircuitHalfOpens = 1
+)
+
+// CircuitOpenErr matches every CircuitOpenError with errors.Is.
+var CircuitOpenErr = errors.New("circuit breaker is open")
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v, retry after %v", CircuitOpenErr, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == CircuitOpenErr
+}
+
+// WithCircuitBreaker fails requests fast with a CircuitOpenError after HowLongToBeat failed repeatedly, instead of
+// waiting for every request to time out. Network errors, timeouts and responses with status 429 or 5xx count as
+// failures. CircuitBreakerOptions is optional.
+func WithCircuitBreaker(options *CircuitBreakerOptions) Option {
+	return func(client *Client) {
+		var opts CircuitBreakerOptions
+		if options != nil {
+			opts = *options
+		}
+
+		if opts.ConsecutiveFailures < 1 {
+			opts.ConsecutiveFailures = defaultCircuitFailures
+		}
+
+		if opts.Window < 1 {
+			opts.Window = defaultCircuitWindow
+		}
+
+		if opts.Cooldown <= 0 {
+			opts.Cooldown = defaultCircuitCooldown
+		}
+
+		if opts.HalfOpenRequests < 1 {
+			opts.HalfOpenRequests = defaultCircuitHalfOpens
+		}
+
+		client.breaker = &circuitBreaker{options: opts}
+	}
+}
+
+// CircuitState returns the state of the circuit breaker. Without a circuit breaker, the circuit is always closed.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+
+	return c.breaker.state
+}
+
+// allowRequest checks the circuit breaker before a request is sent. The returned function records the outcome.
+func (c *Client) allowRequest(ctx context.Context) (func(resp *http.Response, err error), error) {
+	if c.breaker == nil {
+		return func(*http.Response, error) {}, nil
+	}
+
+	generation, err := c.breaker.allow(c.circuitChanged(ctx), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(resp *http.Response, err error) {
+		c.breaker.record(c.circuitChanged(ctx), generation, circuitOutcomeOf(ctx, resp, err), time.Now())
+	}, nil
+}
+
+// circuitChanged returns the function notifying about state changes of the circuit breaker.
+func (c *Client) circuitChanged(ctx context.Context) func(from, to CircuitState) {
+	return func(from, to CircuitState) {
+		c.log(ctx, slog.LevelWarn, "circuit breaker state changed",
+			slog.String("from", from.String()),
+			slog.String("to", to.String()),
+		)
+
+		if c.breaker.options.OnStateChange != nil {
+			c.breaker.options.OnStateChange(from, to)
+		}
+	}
+}
+
+// allow returns a CircuitOpenError if the request must not be sent, otherwise the generation to record the outcome
+// with.
+func (b *circuitBreaker) allow(changed func(from, to CircuitState), now time.Time) (int, error) {
+	b.mu.Lock()
+
+	var transition func()
+
+	switch b.state {
+	case CircuitOpen:
+		if now.Before(b.openUntil) {
+			b.mu.Unlock()
+			return 0, &CircuitOpenError{RetryAfter: b.openUntil.Sub(now)}
+		}
+
+		transition = b.setState(CircuitHalfOpen, changed)
+		fallthrough
+	case CircuitHalfOpen:
+		if b.probes+b.successes >= b.options.HalfOpenRequests {
+			b.mu.Unlock()
+			return 0, &CircuitOpenError{RetryAfter: b.options.Cooldown}
+		}
+
+		b.probes++
+	}
+
+	generation := b.generation
+	b.mu.Unlock()
+
+	if transition != nil {
+		transition()
+	}
+
+	return generation, nil
+}
+
+// record updates the circuit breaker with the outcome of a request let through by allow.
+func (b *circuitBreaker) record(changed func(from, to CircuitState), generation int, outcome circuitOutcome, now time.Time) {
+	b.mu.Lock()
+
+	if generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+
+	var transition func()
+
+	switch b.state {
+	case CircuitHalfOpen:
+		b.probes--
+
+		switch outcome {
+		case circuitFailure:
+			transition = b.open(changed, now)
+		case circuitSuccess:
+			b.successes++
+			if b.successes >= b.options.HalfOpenRequests {
+				transition = b.setState(CircuitClosed, changed)
+			}
+		}
+	case CircuitClosed:
+		if outcome == circuitIgnored {
+			break
+		}
+
+		failed := outcome == circuitFailure
+
+		if failed {
+			b.failures++
+		} else {
+			b.failures = 0
+		}
+
+		if b.options.FailureRate > 0 {
+			if len(b.outcomes) < b.options.Window {
+				b.outcomes = append(b.outcomes, failed)
+			} else {
+				b.outcomes[b.next] = failed
+				b.next = (b.next + 1) % b.options.Window
+			}
+		}
+
+		if b.failures >= b.options.ConsecutiveFailures || b.failureRateExceeded() {
+			transition = b.open(changed, now)
+		}
+	}
+
+	b.mu.Unlock()
+
+	if transition != nil {
+		transition()
+	}
+}
+
+// failureRateExceeded reports whether the failure rate over a full window reached the threshold.
+func (b *circuitBreaker) failureRateExceeded() bool {
+	if b.options.FailureRate <= 0 || len(b.outcomes) < b.options.Window {
+		return false
+	}
+
+	var failures int
+	for _, failed := range b.outcomes {
+		if failed {
+			failures++
+		}
+	}
+
+	return float64(failures)/float64(len(b.outcomes)) >= b.options.FailureRate
+}
+
+// open opens the circuit for the cooldown. It must be called with mu held.
+func (b *circuitBreaker) open(changed func(from, to CircuitState), now time.Time) func() {
+	b.openUntil = now.Add(b.options.Cooldown)
+	return b.setState(CircuitOpen, changed)
+}
+
+// setState resets the counters of the new state and returns the notification, which is called after mu was
+// released. It must be called with mu held.
+func (b *circuitBreaker) setState(state CircuitState, changed func(from, to CircuitState)) func() {
+	from := b.state
+
+	b.state = state
+	b.failures = 0
+	b.outcomes = b.outcomes[:0]
+	b.next = 0
+	b.probes = 0
+	b.successes = 0
+	b.generation++
+
+	return func() {
+		changed(from, state)
+	}
+}
+
+// circuitOutcomeOf classifies the outcome of a request. Requests canceled by the caller are ignored.
+func circuitOutcomeOf(ctx context.Context, resp *http.Response, err error) circuitOutcome {
+	if err != nil {
+		var netErr net.Error
+		if ctx.Err() == nil && (errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)) {
+			return circuitFailure
+		}
+
+		return circuitIgnored
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return circuitFailure
+	}
+
+	return circuitSuccess
+}
@@ -0,0 +1,127 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_WithCircuitBreaker(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		changes []string
+	)
+
+	server := newMockServer(t)
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	mockClient := server.client(t, WithCircuitBreaker(&CircuitBreakerOptions{
+		ConsecutiveFailures: 2,
+		Cooldown:            50 * time.Millisecond,
+		OnStateChange: func(from, to CircuitState) {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, from.String()+" -> "+to.String())
+		},
+	}))
+
+	for i := 0; i < 2; i++ {
+		var statusErr *StatusError
+		if _, err := mockClient.Search(ctx, "witcher", SearchModifierNone, nil); !errors.As(err, &statusErr) {
+			t.Fatalf("Search() expected a StatusError, but received: %v", err)
+		}
+	}
+
+	var circuitErr *CircuitOpenError
+
+	_, err := mockClient.Search(ctx, "witcher", SearchModifierNone, nil)
+	if !errors.As(err, &circuitErr) || !errors.Is(err, CircuitOpenErr) || circuitErr.RetryAfter <= 0 {
+		t.Fatalf(`Search() expected "%v" error, but received: %v`, CircuitOpenErr, err)
+	}
+
+	if calls := server.searchCalls.Load(); calls != 2 || mockClient.CircuitState() != CircuitOpen {
+		t.Fatalf("search calls = %d, state = %v, want no request while open", calls, mockClient.CircuitState())
+	}
+
+	if kind := ErrorKind(err); kind != ErrorKindCircuitOpen {
+		t.Errorf("ErrorKind() = %q, want %q", kind, ErrorKindCircuitOpen)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	server.searchHandler = nil
+
+	if _, err = mockClient.Search(ctx, "witcher", SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() after cooldown error = %v", err)
+	}
+
+	want := []string{"closed -> open", "open -> half-open", "half-open -> closed"}
+	if !reflect.DeepEqual(changes, want) || mockClient.CircuitState() != CircuitClosed {
+		t.Errorf("state changes = %v, want %v", changes, want)
+	}
+}
+
+func Test_circuitBreaker_HalfOpen(t *testing.T) {
+	breaker := &circuitBreaker{options: CircuitBreakerOptions{ConsecutiveFailures: 1, Cooldown: time.Minute, HalfOpenRequests: 2}}
+	changed := func(from, to CircuitState) {}
+	now := time.Now()
+
+	generation, _ := breaker.allow(changed, now)
+	breaker.record(changed, generation, circuitFailure, now)
+
+	now = now.Add(time.Minute)
+
+	first, err := breaker.allow(changed, now)
+	if err != nil || breaker.state != CircuitHalfOpen {
+		t.Fatalf("allow() after cooldown error = %v, state = %v", err, breaker.state)
+	}
+
+	second, _ := breaker.allow(changed, now)
+
+	// Only two probes are let through.
+	var openErr *CircuitOpenError
+	if _, err = breaker.allow(changed, now); !errors.As(err, &openErr) || openErr.RetryAfter != time.Minute {
+		t.Fatalf(`allow() expected "%v" error with the cooldown, but received: %v`, CircuitOpenErr, err)
+	}
+
+	breaker.record(changed, first, circuitSuccess, now)
+	breaker.record(changed, second, circuitFailure, now)
+
+	if breaker.state != CircuitOpen || !breaker.openUntil.Equal(now.Add(time.Minute)) {
+		t.Fatalf("state = %v, open until %v, want a failed probe to open the circuit", breaker.state, breaker.openUntil)
+	}
+
+	// Outcomes of requests let through before the last state change are dropped.
+	breaker.record(changed, generation, circuitSuccess, now)
+
+	if breaker.state != CircuitOpen {
+		t.Errorf("state = %v, want %v", breaker.state, CircuitOpen)
+	}
+}
+
+func Test_circuitBreaker_FailureRate(t *testing.T) {
+	breaker := &circuitBreaker{options: CircuitBreakerOptions{ConsecutiveFailures: 10, FailureRate: 0.5, Window: 4, Cooldown: time.Minute}}
+	changed := func(from, to CircuitState) {}
+	now := time.Now()
+
+	for i, outcome := range []circuitOutcome{circuitFailure, circuitSuccess, circuitIgnored, circuitFailure, circuitSuccess} {
+		if breaker.state != CircuitClosed {
+			t.Fatalf("state after %d requests = %v, want %v", i, breaker.state, CircuitClosed)
+		}
+
+		generation, _ := breaker.allow(changed, now)
+		breaker.record(changed, generation, outcome, now)
+	}
+
+	if breaker.state != CircuitOpen {
+		t.Errorf("state = %v, want %v after 2 of the last 4 requests failed", breaker.state, CircuitOpen)
+	}
+}
@@ -2,20 +2,60 @@ package howlongtobeat
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type (
 	Client struct {
 		client  *http.Client
-		logger  *log.Logger
+		logger  *slog.Logger
 		apiData *ApiData
+		baseURL string
+		cache   *resultCache
+
+		staleOptions *StaleOptions
+		flights      *flightGroup
+		searchRetry  bool
+
+		timeout           time.Duration
+		operationTimeouts map[Operation]time.Duration
+		transportConfig   *TransportConfig
+		maxBodySizes      map[Operation]int64
+
+		discoveryConcurrency int
+
+		driftHook   func(drift SchemaDrift)
+		rawPayloads bool
+		middleware  []Middleware
+		observers   []Observer
+
+		headerProfiles []HeaderProfile
+		headerIndex    atomic.Uint64
+
+		proxies *proxyPool
+		breaker *circuitBreaker
+		// optionErr is the first error of an option, returned by New.
+		optionErr error
+
+		// apiMu guards apiData, discoverEndpoint and tokenRejected, so concurrent requests share a single token.
+		apiMu sync.Mutex
+		// discoverEndpoint is set once the default search endpoint was rejected, the endpoint is then discovered from
+		// the scripts of the homepage.
+		discoverEndpoint bool
+		// tokenRejected is set from a rejected token until a new token was fetched.
+		tokenRejected bool
+
+		// mu guards the caches below.
+		mu       sync.Mutex
+		steamIDs map[int]SearchGameData
 	}
 
 	// ApiData contains the data needed to make requests to the HLTB API.
@@ -27,40 +67,55 @@ type (
 
 	// Option is a type alias for functions to configure your Client.
 	Option func(client *Client)
+
+	// StatusError is returned if HowLongToBeat responds with an unexpected status code.
+	StatusError struct {
+		StatusCode int
+	}
 )
 
-// WithRequestTimeout sets the timeout for outgoing requests.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// WithRequestTimeout sets the timeout for outgoing requests in seconds.
 // If timeout duration is set to 0, the default timeout of 30 seconds will be used.
-// If using the WithHTTPClient option, make sure to set your client before the timeout.
+//
+// Deprecated: Use WithTimeout.
 func WithRequestTimeout(timeout int) Option {
-	return func(client *Client) {
-		if timeout > 0 {
-			client.client.Timeout = time.Duration(timeout) * time.Second
-		}
-	}
+	return WithTimeout(time.Duration(timeout) * time.Second)
 }
 
 // WithHTTPClient sets the user provided HTTP client to use it for outgoing requests.
-// When using the WithRequestTimeout option, make sure to set your client before the timeout.
+// The client is not modified, options changing it, like WithTimeout, apply to a copy.
 func WithHTTPClient(httpClient *http.Client) Option {
 	return func(client *Client) {
 		client.client = httpClient
 	}
 }
 
+// WithBaseURL sets the base URL all requests are sent to, e.g. a mirror, a caching proxy or a fake server in tests.
+// The default is https://howlongtobeat.com.
+func WithBaseURL(baseURL string) Option {
+	return func(client *Client) {
+		if baseURL != "" {
+			client.baseURL = strings.TrimSuffix(baseURL, "/")
+		}
+	}
+}
+
 // New creates a new HowLongToBeat client for optimized HTTP requests.
+// Options are applied in the given order, a later option overrides an earlier one. The HTTP client is set up after
+// all options were applied: first the timeout of WithTimeout, then the transport config of WithTransportConfig and
+// last the proxy pool of WithProxyPool. All of them apply to a copy, a client set with WithHTTPClient is never
+// modified.
 func New(options ...Option) (*Client, error) {
 	c := &Client{
 		client: &http.Client{
-			Transport: &http.Transport{
-				MaxIdleConns:          10,
-				IdleConnTimeout:       15 * time.Second,
-				ResponseHeaderTimeout: 15 * time.Second,
-				DisableKeepAlives:     false,
-				ForceAttemptHTTP2:     true,
-			},
-			Timeout: defaultRequestTimeout,
+			Transport: defaultTransport(),
+			Timeout:   defaultRequestTimeout,
 		},
+		baseURL: hltbBaseURL,
 	}
 
 	// Apply options
@@ -68,47 +123,162 @@ func New(options ...Option) (*Client, error) {
 		opt(c)
 	}
 
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+
+	if c.timeout > 0 {
+		httpClient := *c.client
+		httpClient.Timeout = c.timeout
+		c.client = &httpClient
+	}
+
+	if c.transportConfig != nil {
+		if err := c.setupTransport(); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.proxies != nil {
+		if err := c.setupProxyPool(); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.cache != nil && c.staleOptions != nil {
+		c.cache.refreshAfter = c.staleOptions.RefreshAfter
+		c.cache.maxStale = c.staleOptions.MaxStale
+	}
+
 	return c, nil
 }
 
 // do performs the given request and parses the response with the provided parser.
 func (c *Client) do(req *http.Request, parser parseResponseFunc) (err error) {
+	ctx := req.Context()
+	op, _ := OperationFromContext(ctx)
+
+	defer func() {
+		if err != nil && !scanStopped(ctx) {
+			c.onError(op, req, err)
+		}
+	}()
+
+	if err = c.beforeRequest(op, req); err != nil {
+		return err
+	}
+
+	reportCircuit, err := c.allowRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, reportProxy := c.withProxy(req)
+
+	// The timeout is applied last, so an expired operation timeout counts as a failure of the proxy.
+	opCtx, cancel := c.operationContext(req.Context(), op)
+	defer cancel()
+
+	req = req.WithContext(opCtx)
+	start := time.Now()
+
 	resp, err := c.client.Do(req)
+	reportProxy(resp, err)
+	reportCircuit(resp, err)
 	if err != nil {
+		if !scanStopped(ctx) {
+			c.log(ctx, slog.LevelWarn, "request failed",
+				slog.String("operation", string(op)),
+				slog.String("method", req.Method),
+				slog.String("url", req.URL.Redacted()),
+				slog.Duration("latency", time.Since(start)),
+				slog.Any("error", err),
+			)
+		}
 		return err
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
+	c.log(ctx, slog.LevelDebug, "request completed",
+		slog.String("operation", string(op)),
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.Redacted()),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("latency", time.Since(start)),
+	)
+
+	c.afterResponse(op, req, resp)
+
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return parser(resp)
+		if err = c.limitBody(op, resp); err != nil {
+			c.log(ctx, slog.LevelError, "response too large",
+				slog.String("operation", string(op)),
+				slog.String("url", req.URL.Redacted()),
+				slog.Int64("content_length", resp.ContentLength),
+			)
+			return err
+		}
+
+		if err = parser(resp); err != nil {
+			c.log(ctx, slog.LevelError, "failed to parse response",
+				slog.String("operation", string(op)),
+				slog.String("method", req.Method),
+				slog.String("url", req.URL.Redacted()),
+				slog.Any("error", err),
+			)
+		}
+		return err
 	default:
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return &StatusError{StatusCode: resp.StatusCode}
 	}
 }
 
 // request creates a new HTTP request with the default headers and context.
-func (c *Client) request(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+// The operation is stored in the context of the request for logging and middleware.
+func (c *Client) request(ctx context.Context, op Operation, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, operationKey{}, op), method, url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set(http.CanonicalHeaderKey("User-Agent"), "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/115.0.0.0 Safari/537.36")
+	c.headerProfile().apply(req)
 	req.Header.Set(http.CanonicalHeaderKey("Origin"), "https://howlongtobeat.com/")
 	req.Header.Set(http.CanonicalHeaderKey("Referer"), "https://howlongtobeat.com/")
 
 	return req, nil
 }
 
+// url returns the absolute URL of the given path on the configured base URL.
+func (c *Client) url(path string) string {
+	if c.baseURL == "" {
+		return hltbBaseURL + path
+	}
+
+	return c.baseURL + path
+}
+
 func (c *Client) getApiData(ctx context.Context) (*ApiData, error) {
+	c.apiMu.Lock()
+	defer c.apiMu.Unlock()
+
 	if c.apiData != nil {
 		return c.apiData, nil
 	}
 
-	apiData, err := c.getApiDataWithDefaultEndpoint(ctx)
+	var (
+		apiData *ApiData
+		err     error
+	)
+
+	if c.discoverEndpoint {
+		apiData, err = c.getApiDataWithEndpointSearch(ctx)
+	} else {
+		apiData, err = c.getApiDataWithDefaultEndpoint(ctx)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -118,18 +288,29 @@ func (c *Client) getApiData(ctx context.Context) (*ApiData, error) {
 	return c.apiData, nil
 }
 
+// invalidateApiData drops the given api data after HowLongToBeat rejected it, so the next request fetches a new token.
+// If discover is set, the search endpoint is discovered from now on instead of using the default endpoint.
+func (c *Client) invalidateApiData(stale *ApiData, discover bool) {
+	c.apiMu.Lock()
+	defer c.apiMu.Unlock()
+
+	if c.apiData == stale {
+		c.apiData = nil
+		c.tokenRejected = true
+	}
+
+	if discover {
+		c.discoverEndpoint = true
+	}
+}
+
 // getApiDataWithDefaultEndpoint
 // Method parses the request token and sets the default endpointPath.
 func (c *Client) getApiDataWithDefaultEndpoint(ctx context.Context) (*ApiData, error) {
 	apiData := &ApiData{}
 
-	req, err := c.tokenHTTPRequest(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("create token request: %w", err)
-	}
-
-	if err = c.do(req, c.tokenParser(apiData)); err != nil {
-		return nil, fmt.Errorf("fetch token: %w", err)
+	if err := c.fetchToken(ctx, apiData); err != nil {
+		return nil, err
 	}
 
 	apiData.endpointPath = hltbSearchEndpoint
@@ -143,48 +324,91 @@ func (c *Client) getApiDataWithDefaultEndpoint(ctx context.Context) (*ApiData, e
 func (c *Client) getApiDataWithEndpointSearch(ctx context.Context) (*ApiData, error) {
 	apiData := &ApiData{}
 
-	req, err := c.tokenHTTPRequest(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("create token request: %w", err)
+	if err := c.fetchToken(ctx, apiData); err != nil {
+		return nil, err
 	}
 
-	if err = c.do(req, c.tokenParser(apiData)); err != nil {
-		return nil, fmt.Errorf("fetch token: %w", err)
+	if err := c.discoverEndpointPath(ctx, apiData); err != nil {
+		return nil, err
 	}
 
-	req, err = c.scriptPathHTTPRequest(ctx)
+	return apiData, nil
+}
+
+// discoverEndpointPath finds the search endpoint in the scripts of the homepage and stores it in apiData.
+// The scripts are scanned concurrently, see scanScripts.
+func (c *Client) discoverEndpointPath(ctx context.Context, apiData *ApiData) (err error) {
+	ctx, obs := c.observe(ctx, OperationDiscovery)
+	info := OperationInfo{}
+
+	defer func() {
+		info.Err = err
+		obs.end(info)
+	}()
+
+	ctx, cancel := c.operationContext(ctx, OperationDiscovery)
+	defer cancel()
+
+	req, err := c.scriptPathHTTPRequest(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("create script path request: %w", err)
+		return fmt.Errorf("create script path request: %w", err)
 	}
 
 	if err = c.do(req, c.scriptParser(apiData)); err != nil {
-		return nil, fmt.Errorf("fetch script path: %w", err)
+		return fmt.Errorf("fetch script path: %w", err)
 	}
 
-	for _, scriptPath := range apiData.scriptPaths {
-		req, err = c.endpointPathHTTPRequest(ctx, scriptPath)
-		if err != nil {
-			return nil, fmt.Errorf("create endpoint request: %w", err)
-		}
+	apiData.endpointPath, info.Scripts, err = c.scanScripts(ctx, apiData.scriptPaths)
+	if err != nil {
+		return err
+	}
 
-		if err = c.do(req, c.endpointParser(apiData)); err != nil {
-			return nil, fmt.Errorf("fetch endpoint: %w", err)
-		}
+	if apiData.endpointPath == "" {
+		c.log(ctx, slog.LevelWarn, "search endpoint not found", slog.Int("scripts", len(info.Scripts)))
+		return &EndpointNotFoundError{Scanned: info.Scripts}
+	}
 
-		if apiData.endpointPath != "" {
-			break
-		}
+	return nil
+}
+
+// fetchToken fetches a new auth token into apiData. It must be called with apiMu held.
+func (c *Client) fetchToken(ctx context.Context, apiData *ApiData) error {
+	if err := c.requestToken(ctx, apiData, c.tokenRejected); err != nil {
+		return err
 	}
 
-	if apiData.endpointPath == "" {
-		return nil, errors.New("empty endpoint path")
+	c.tokenRejected = false
+
+	return nil
+}
+
+// requestToken requests a new auth token into apiData. refresh is reported to observers, it is set if the token
+// replaces a rejected one.
+func (c *Client) requestToken(ctx context.Context, apiData *ApiData, refresh bool) (err error) {
+	ctx, obs := c.observe(ctx, OperationToken)
+	info := OperationInfo{Refresh: refresh}
+
+	defer func() {
+		info.Err = err
+		obs.end(info)
+	}()
+
+	req, err := c.tokenHTTPRequest(ctx)
+	if err != nil {
+		return fmt.Errorf("create token request: %w", err)
 	}
 
-	return apiData, nil
+	if err = c.do(req, c.tokenParser(apiData)); err != nil {
+		return fmt.Errorf("fetch token: %w", err)
+	}
+
+	c.log(ctx, slog.LevelInfo, "fetched auth token", slog.Bool("refresh", refresh))
+
+	return nil
 }
 
 func (c *Client) tokenHTTPRequest(ctx context.Context) (*http.Request, error) {
-	req, err := c.request(ctx, http.MethodGet, hltbTokenURL, nil)
+	req, err := c.request(ctx, OperationToken, http.MethodGet, c.url(hltbTokenPath), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -199,9 +423,9 @@ func (c *Client) tokenHTTPRequest(ctx context.Context) (*http.Request, error) {
 }
 
 func (c *Client) scriptPathHTTPRequest(ctx context.Context) (*http.Request, error) {
-	return c.request(ctx, http.MethodGet, hltbBaseURL, nil)
+	return c.request(ctx, OperationScript, http.MethodGet, c.url(""), nil)
 }
 
 func (c *Client) endpointPathHTTPRequest(ctx context.Context, path string) (*http.Request, error) {
-	return c.request(ctx, http.MethodGet, hltbBaseURL+path, nil)
+	return c.request(ctx, OperationEndpoint, http.MethodGet, c.url(path), nil)
 }
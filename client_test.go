@@ -70,22 +70,25 @@ func TestWithCustomHTTPClient(t *testing.T) {
 }
 
 func TestNewWithOptions(t *testing.T) {
-	customHTTPClient := http.DefaultClient
-
-	mockClient, err := New(
-		WithHTTPClient(customHTTPClient),
-		WithRequestTimeout(10),
-	)
-	if err != nil {
-		t.Fatalf("New() returned error: %v", err)
-	}
+	transport := &http.Transport{}
+
+	// The order of the options does not matter.
+	for _, options := range [][]Option{
+		{WithHTTPClient(&http.Client{Transport: transport}), WithRequestTimeout(10)},
+		{WithRequestTimeout(10), WithHTTPClient(&http.Client{Transport: transport})},
+	} {
+		mockClient, err := New(options...)
+		if err != nil {
+			t.Fatalf("New() returned error: %v", err)
+		}
 
-	if mockClient.client.Timeout != 10*time.Second {
-		t.Fatalf("WithRequestTimeout() did not set the custom timeout")
-	}
+		if mockClient.client.Timeout != 10*time.Second {
+			t.Fatalf("WithRequestTimeout() did not set the custom timeout")
+		}
 
-	if mockClient.client != customHTTPClient {
-		t.Fatalf("WithHTTPClient() did not set the custom HTTP client")
+		if mockClient.client.Transport != transport {
+			t.Fatalf("WithHTTPClient() did not set the custom HTTP client")
+		}
 	}
 }
 
@@ -164,3 +167,35 @@ func Test_do_Invalid_Status(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestWithBaseURL(t *testing.T) {
+	mockClient, err := New(WithBaseURL("http://localhost:8080/"))
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	if got := mockClient.url(hltbGamePath); got != "http://localhost:8080/game" {
+		t.Fatalf("WithBaseURL() did not set the base URL, received: %s", got)
+	}
+}
+
+func Test_do_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	c := Client{
+		client: server.Client(),
+	}
+
+	var statusErr *StatusError
+	if err = c.do(req, nil); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
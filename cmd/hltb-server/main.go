@@ -0,0 +1,111 @@
+// Command hltb-server serves HowLongToBeat search results and game details as a JSON REST API.
+// See the server package for the available endpoints.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+	"github.com/forbiddencoding/howlongtobeat/hltbprom"
+	"github.com/forbiddencoding/howlongtobeat/server"
+)
+
+func main() {
+	var (
+		addr      = flag.String("addr", ":8080", "listen address")
+		baseURL   = flag.String("base-url", "", "base URL of HowLongToBeat (default https://howlongtobeat.com)")
+		cacheTTL  = flag.Duration("cache-ttl", 15*time.Minute, "time to live of cached responses, 0 disables the cache")
+		cacheSize = flag.Int("cache-size", 1000, "maximum number of cached responses")
+		maxStale  = flag.Duration("max-stale", 24*time.Hour, "time expired responses are kept to be served while HowLongToBeat fails")
+		refresh   = flag.Duration("refresh-after", 0, "age after which cached responses are refreshed in the background, 0 disables it")
+		rate      = flag.Float64("rate", 5, "requests per second per client, 0 disables rate limiting")
+		burst     = flag.Int("burst", 10, "maximum burst of requests per client")
+		metrics   = flag.Bool("metrics", true, "serve Prometheus metrics on /metrics")
+		failures  = flag.Int("breaker-failures", 5, "consecutive upstream failures opening the circuit breaker, 0 disables it")
+		cooldown  = flag.Duration("breaker-cooldown", 30*time.Second, "time the circuit breaker stays open")
+		maxSize   = flag.Int("max-page-size", 100, "largest page size accepted by /search")
+		drain     = flag.Duration("drain", 5*time.Second, "time /readyz fails before the server shuts down, so load balancers stop sending requests")
+	)
+	flag.Parse()
+
+	clientOptions := []howlongtobeat.Option{
+		howlongtobeat.WithBaseURL(*baseURL),
+		howlongtobeat.WithCache(*cacheTTL, *cacheSize),
+		howlongtobeat.WithRequestCoalescing(),
+		howlongtobeat.WithLogger(slog.Default()),
+	}
+	serverOptions := []server.Option{server.WithRateLimit(*rate, *burst), server.WithMaxPageSize(*maxSize)}
+
+	if *maxStale > 0 || *refresh > 0 {
+		clientOptions = append(clientOptions, howlongtobeat.WithStaleWhileRevalidate(&howlongtobeat.StaleOptions{
+			RefreshAfter: *refresh,
+			MaxStale:     *maxStale,
+		}))
+	}
+
+	if *failures > 0 {
+		clientOptions = append(clientOptions, howlongtobeat.WithCircuitBreaker(&howlongtobeat.CircuitBreakerOptions{
+			ConsecutiveFailures: *failures,
+			Cooldown:            *cooldown,
+		}))
+	}
+
+	if *metrics {
+		collector := hltbprom.NewCollector()
+		clientOptions = append(clientOptions, howlongtobeat.WithObserver(collector))
+		serverOptions = append(serverOptions, server.WithMetrics(collector), server.WithRateLimitHook(collector.ObserveRateLimit))
+	}
+
+	client, err := howlongtobeat.New(clientOptions...)
+	if err != nil {
+		log.Fatalf("create client: %v", err)
+	}
+
+	handler := server.New(client, serverOptions...)
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// shutdown receives the result of Shutdown, so main waits for the requests in flight.
+	shutdown := make(chan error, 1)
+
+	go func() {
+		<-ctx.Done()
+		// A second signal terminates the server right away.
+		stop()
+
+		handler.Drain()
+		log.Printf("draining for %v", *drain)
+		time.Sleep(*drain)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		shutdown <- srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("listening on %s", *addr)
+
+	if err = srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("listen: %v", err)
+	}
+
+	if err = <-shutdown; err != nil {
+		log.Fatalf("shutdown: %v", err)
+	}
+}
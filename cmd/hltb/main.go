@@ -0,0 +1,407 @@
+// Command hltb queries HowLongToBeat from the command line.
+//
+// Usage:
+//
+//	hltb search [flags] <term>
+//	hltb search-simple [flags] <term>
+//	hltb detail [flags] <game id>
+//	hltb detail-simple [flags] <game id>
+//	hltb schema [flags] search <term> | detail <game id>
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+	"github.com/forbiddencoding/howlongtobeat/export"
+)
+
+// Exit codes of the hltb command.
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitUpstream = 4
+	exitTimeout  = 5
+	exitNetwork  = 6
+	exitDrift    = 7
+)
+
+type (
+	// flags are the flags shared by all subcommands.
+	flags struct {
+		format   string
+		columns  string
+		timeout  time.Duration
+		baseURL  string
+		modifier string
+		page     int
+		size     int
+		file     string
+	}
+
+	command struct {
+		name   string
+		usage  string
+		search bool
+		schema bool
+		run    func(ctx context.Context, client *howlongtobeat.Client, f *flags, arg string, stdout io.Writer) error
+	}
+)
+
+var (
+	notFoundErr = errors.New("no game found")
+	usageErr    = errors.New("usage error")
+	driftErr    = errors.New("schema drift detected")
+)
+
+var commands = []command{
+	{name: "search", usage: "<term>", search: true, run: runSearch},
+	{name: "search-simple", usage: "<term>", search: true, run: runSearchSimple},
+	{name: "detail", usage: "<game id>", run: runDetail},
+	{name: "detail-simple", usage: "<game id>", run: runDetailSimple},
+	{name: "schema", usage: "search <term> | detail <game id>", schema: true, run: runSchema},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+		}
+	}
+
+	if cmd == nil {
+		if args[0] != "-h" && args[0] != "-help" && args[0] != "help" {
+			_, _ = fmt.Fprintf(stderr, "hltb: unknown command %q\n", args[0])
+		}
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	f := &flags{}
+
+	fs := flag.NewFlagSet("hltb "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: hltb %s [flags] %s\n", cmd.name, cmd.usage)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&f.format, "format", "table", "output format: table, markdown, json, ndjson or csv")
+	fs.StringVar(&f.columns, "columns", "", "comma separated list of columns for table, markdown, ndjson and csv output")
+	fs.DurationVar(&f.timeout, "timeout", 30*time.Second, "timeout for the whole command")
+	fs.StringVar(&f.baseURL, "base-url", "", "base URL of HowLongToBeat (default https://howlongtobeat.com)")
+
+	if cmd.search {
+		fs.StringVar(&f.modifier, "modifier", "", "search modifier: only_dlc or hide_dlc")
+		fs.IntVar(&f.page, "page", 1, "result page")
+		fs.IntVar(&f.size, "size", 20, "results per page")
+	}
+
+	if cmd.schema {
+		fs.StringVar(&f.file, "file", "", "saved payload to check instead of a live request: a search response or a game page")
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	client, err := howlongtobeat.New(howlongtobeat.WithBaseURL(f.baseURL))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "hltb: %v\n", err)
+		return exitError
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+
+	if err = cmd.run(ctx, client, f, strings.Join(fs.Args(), " "), stdout); err != nil {
+		_, _ = fmt.Fprintf(stderr, "hltb: %v\n", err)
+		return exitCode(err)
+	}
+
+	return exitOK
+}
+
+func printUsage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "usage: hltb <command> [flags] <args>")
+	_, _ = fmt.Fprintln(w, "\ncommands:")
+
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.usage)
+	}
+
+	_, _ = fmt.Fprintln(w, "\nRun 'hltb <command> -h' for the flags of a command.")
+}
+
+// exitCode maps an error to the exit code of the command.
+func exitCode(err error) int {
+	var (
+		statusErr *howlongtobeat.StatusError
+		netErr    net.Error
+	)
+
+	switch {
+	case errors.Is(err, usageErr),
+		errors.Is(err, howlongtobeat.EmptySearchTermErr),
+		errors.Is(err, howlongtobeat.GameIDRequiredErr),
+		errors.Is(err, howlongtobeat.UnknownPayloadKindErr),
+		errors.Is(err, export.UnknownFormatErr),
+		errors.Is(err, export.UnknownColumnErr):
+		return exitUsage
+	case errors.Is(err, driftErr):
+		return exitDrift
+	case errors.Is(err, notFoundErr), errors.Is(err, howlongtobeat.GameNotFoundErr):
+		return exitNotFound
+	case errors.As(err, &statusErr):
+		if statusErr.StatusCode == http.StatusNotFound {
+			return exitNotFound
+		}
+		return exitUpstream
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return exitTimeout
+	case errors.As(err, &netErr):
+		return exitNetwork
+	default:
+		return exitError
+	}
+}
+
+func runSearch(ctx context.Context, client *howlongtobeat.Client, f *flags, term string, stdout io.Writer) error {
+	result, err := search(ctx, client, f, term)
+	if err != nil {
+		return err
+	}
+
+	return write(stdout, f, result, func(e *export.Encoder) error { return e.EncodeSearch(result) })
+}
+
+func runSearchSimple(ctx context.Context, client *howlongtobeat.Client, f *flags, term string, stdout io.Writer) error {
+	result, err := search(ctx, client, f, term)
+	if err != nil {
+		return err
+	}
+
+	simple := result.Reduce()
+
+	return write(stdout, f, simple, func(e *export.Encoder) error { return e.EncodeSearchSimple(simple) })
+}
+
+func runDetail(ctx context.Context, client *howlongtobeat.Client, f *flags, arg string, stdout io.Writer) error {
+	details, err := detail(ctx, client, arg)
+	if err != nil {
+		return err
+	}
+
+	return write(stdout, f, details, func(e *export.Encoder) error { return e.EncodeDetails(details) })
+}
+
+func runDetailSimple(ctx context.Context, client *howlongtobeat.Client, f *flags, arg string, stdout io.Writer) error {
+	details, err := detail(ctx, client, arg)
+	if err != nil {
+		return err
+	}
+
+	simple := details.Reduce()
+
+	return write(stdout, f, simple, func(e *export.Encoder) error { return e.EncodeDetailSimple(simple) })
+}
+
+// runSchema compares a live or saved payload to the structs of the howlongtobeat package.
+// Live requests use their own client, since the drift hook has to be set when the client is created.
+func runSchema(ctx context.Context, _ *howlongtobeat.Client, f *flags, arg string, stdout io.Writer) error {
+	kind, query, _ := strings.Cut(arg, " ")
+
+	var (
+		drift *howlongtobeat.SchemaDrift
+		err   error
+	)
+
+	if f.file != "" {
+		payload, err := os.ReadFile(f.file)
+		if err != nil {
+			return err
+		}
+
+		if drift, err = howlongtobeat.DiffSchema(howlongtobeat.PayloadKind(kind), payload); err != nil {
+			return err
+		}
+	} else {
+		if drift, err = liveSchemaDrift(ctx, f, howlongtobeat.PayloadKind(kind), query); err != nil {
+			return err
+		}
+	}
+
+	if f.format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(drift)
+	} else {
+		err = writeSchemaDrift(stdout, drift)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if drift.HasDrift() {
+		return driftErr
+	}
+
+	return nil
+}
+
+func liveSchemaDrift(ctx context.Context, f *flags, kind howlongtobeat.PayloadKind, query string) (*howlongtobeat.SchemaDrift, error) {
+	if query == "" {
+		return nil, fmt.Errorf("%w: missing search term or game id", usageErr)
+	}
+
+	drift := &howlongtobeat.SchemaDrift{Kind: kind}
+
+	client, err := howlongtobeat.New(howlongtobeat.WithBaseURL(f.baseURL), howlongtobeat.WithSchemaDrift(func(d howlongtobeat.SchemaDrift) {
+		drift = &d
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case howlongtobeat.PayloadSearch:
+		_, err = client.Search(ctx, query, howlongtobeat.SearchModifierNone, nil)
+	case howlongtobeat.PayloadDetail:
+		_, err = detail(ctx, client, query)
+	default:
+		err = fmt.Errorf("%w: %q", howlongtobeat.UnknownPayloadKindErr, kind)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return drift, nil
+}
+
+func writeSchemaDrift(w io.Writer, drift *howlongtobeat.SchemaDrift) error {
+	if !drift.HasDrift() {
+		_, err := fmt.Fprintf(w, "%s: no drift\n", drift.Kind)
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "%s: %d unknown, %d missing\n", drift.Kind, len(drift.Unknown), len(drift.Missing)); err != nil {
+		return err
+	}
+
+	for _, path := range drift.Unknown {
+		if _, err := fmt.Fprintf(w, "unknown  %s\n", path); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range drift.Missing {
+		if _, err := fmt.Fprintf(w, "missing  %s\n", path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func search(ctx context.Context, client *howlongtobeat.Client, f *flags, term string) (*howlongtobeat.SearchGame, error) {
+	modifier, err := parseModifier(f.modifier)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.Search(ctx, term, modifier, &howlongtobeat.SearchOptions{
+		Pagination: &howlongtobeat.SearchGamePagination{Page: f.page, PageSize: f.size},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("%w for %q", notFoundErr, term)
+	}
+
+	return result, nil
+}
+
+func detail(ctx context.Context, client *howlongtobeat.Client, arg string) (*howlongtobeat.GameDetails, error) {
+	gameID, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid game id %q", usageErr, arg)
+	}
+
+	details, err := client.Detail(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(details.Props.PageProps.Game.Data.Game) == 0 {
+		return nil, fmt.Errorf("game %d: %w", gameID, howlongtobeat.GameNotFoundErr)
+	}
+
+	return details, nil
+}
+
+func parseModifier(modifier string) (howlongtobeat.SearchModifier, error) {
+	switch modifier {
+	case howlongtobeat.SearchModifierNone, howlongtobeat.SearchModifierOnlyDLC, howlongtobeat.SearchModifierHideDLC:
+		return modifier, nil
+	default:
+		return "", fmt.Errorf("%w: invalid modifier %q", usageErr, modifier)
+	}
+}
+
+// write writes the result in the requested format. JSON output is the indented result itself, all other formats are
+// written by the export package.
+func write(stdout io.Writer, f *flags, result any, encode func(e *export.Encoder) error) error {
+	var format export.Format
+
+	switch f.format {
+	case "json":
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case "table":
+		format = export.FormatText
+	default:
+		format = export.Format(f.format)
+	}
+
+	var options []export.Option
+	if f.columns != "" {
+		options = append(options, export.WithColumns(strings.Split(f.columns, ",")...))
+	}
+
+	return encode(export.NewEncoder(stdout, format, options...))
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/finder/init":
+			_, _ = w.Write([]byte(`{"token":"token"}`))
+		case r.URL.Path == "/api/finder":
+			var result howlongtobeat.SearchGame
+
+			var body struct {
+				SearchTerms []string `json:"searchTerms"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			if strings.Join(body.SearchTerms, " ") == "witcher" {
+				result.Data = []howlongtobeat.SearchGameData{{GameID: 10270, GameName: "The Witcher 3: Wild Hunt", CompMain: 185696}}
+			}
+
+			_ = json.NewEncoder(w).Encode(result)
+		case r.URL.Path == "/game/10270":
+			_, _ = fmt.Fprint(w, `<script id="__NEXT_DATA__" type="application/json">`+
+				`{"props":{"pageProps":{"game":{"data":{"game":[{"game_id":10270,"game_name":"The Witcher 3: Wild Hunt","comp_main":185696}]}},"ignWikiNav":[]}}}`+
+				`</script>`)
+		case r.URL.Path == "/game/503":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/game/1":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func Test_run(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  string
+	}{
+		{
+			name:     "search csv",
+			args:     []string{"search", "-format", "csv", "-columns", "game_id,game_name", "witcher"},
+			wantCode: exitOK,
+			wantOut:  "game_id,game_name\n10270,The Witcher 3: Wild Hunt\n",
+		},
+		{
+			name:     "search simple table",
+			args:     []string{"search-simple", "-columns", "game_name,comp_main", "witcher"},
+			wantCode: exitOK,
+			wantOut:  "game_name                 comp_main\nThe Witcher 3: Wild Hunt  52h\n",
+		},
+		{
+			name:     "detail simple json",
+			args:     []string{"detail-simple", "-format", "json", "10270"},
+			wantCode: exitOK,
+			wantOut:  `"game_id": 10270`,
+		},
+		{
+			name:     "search without results",
+			args:     []string{"search", "unknown"},
+			wantCode: exitNotFound,
+		},
+		{
+			name:     "detail not found",
+			args:     []string{"detail", "2"},
+			wantCode: exitNotFound,
+		},
+		{
+			name:     "detail upstream error",
+			args:     []string{"detail", "503"},
+			wantCode: exitUpstream,
+		},
+		{
+			name:     "detail timeout",
+			args:     []string{"detail", "-timeout", "50ms", "1"},
+			wantCode: exitTimeout,
+		},
+		{
+			name:     "invalid game id",
+			args:     []string{"detail", "abc"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "invalid modifier",
+			args:     []string{"search", "-modifier", "dlc", "witcher"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "schema live detail",
+			args:     []string{"schema", "detail", "10270"},
+			wantCode: exitDrift,
+			wantOut:  "missing  props.pageProps.game.count\n",
+		},
+		{
+			name:     "schema unknown payload kind",
+			args:     []string{"schema", "user", "witcher"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "unknown command",
+			args:     []string{"list"},
+			wantCode: exitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			args := append([]string{tt.args[0], "-base-url", server.URL}, tt.args[1:]...)
+			if tt.args[0] == "list" {
+				args = tt.args
+			}
+
+			if code := run(args, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d, stderr: %s", code, tt.wantCode, stderr.String())
+			}
+
+			if !strings.Contains(stdout.String(), tt.wantOut) {
+				t.Errorf("run() stdout = %q, want %q", stdout.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func Test_run_SchemaFile(t *testing.T) {
+	search, err := json.Marshal(howlongtobeat.SearchGame{Data: []howlongtobeat.SearchGameData{{GameID: 10270}}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	searchFile := filepath.Join(t.TempDir(), "search.json")
+	if err = os.WriteFile(searchFile, search, 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  string
+	}{
+		{
+			name:     "search without drift",
+			args:     []string{"schema", "-file", searchFile, "search"},
+			wantCode: exitOK,
+			wantOut:  "search: no drift\n",
+		},
+		{
+			name:     "game page with drift",
+			args:     []string{"schema", "-file", "../../test_files/test_html_parser.html", "detail"},
+			wantCode: exitDrift,
+			wantOut:  "unknown  props.pageProps.related\n",
+		},
+		{
+			name:     "json output",
+			args:     []string{"schema", "-file", "../../test_files/test_html_parser.html", "-format", "json", "detail"},
+			wantCode: exitDrift,
+			wantOut:  `"kind": "detail"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d, stderr: %s", code, tt.wantCode, stderr.String())
+			}
+
+			if !strings.Contains(stdout.String(), tt.wantOut) {
+				t.Errorf("run() stdout = %q, want %q", stdout.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package howlongtobeat
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	// flightGroup de-duplicates identical calls in flight, so they share one upstream request.
+	flightGroup struct {
+		mu      sync.Mutex
+		flights map[string]*flight
+	}
+
+	// flight is a call in flight. It runs on its own context, which is canceled once every waiting caller left.
+	flight struct {
+		done    chan struct{}
+		cancel  context.CancelFunc
+		waiters int
+
+		value any
+		err   error
+	}
+)
+
+// WithRequestCoalescing shares one upstream request between identical concurrent calls of Search and Detail, i.e.
+// calls for the same game or the same normalized search term, modifier, page and page size. A caller whose context is
+// canceled stops waiting with the error of its context, the request is only canceled once all callers stopped
+// waiting. Shared results must not be modified.
+func WithRequestCoalescing() Option {
+	return func(client *Client) {
+		client.flights = &flightGroup{flights: make(map[string]*flight)}
+	}
+}
+
+// do calls fn, unless a call for the same key is in flight, and waits for its result or the end of ctx.
+// Without a flight group, fn is called with ctx directly.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (any, error)) (any, error) {
+	if g == nil {
+		return fn(ctx)
+	}
+
+	g.mu.Lock()
+
+	f, ok := g.flights[key]
+	if ok {
+		f.waiters++
+	} else {
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+		f = &flight{done: make(chan struct{}), cancel: cancel, waiters: 1}
+		g.flights[key] = f
+
+		go g.run(flightCtx, key, f, fn)
+	}
+
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		g.leave(key, f)
+		return nil, ctx.Err()
+	}
+}
+
+func (g *flightGroup) run(ctx context.Context, key string, f *flight, fn func(ctx context.Context) (any, error)) {
+	defer f.cancel()
+
+	f.value, f.err = fn(ctx)
+
+	g.mu.Lock()
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+	g.mu.Unlock()
+
+	close(f.done)
+}
+
+// leave removes a waiting caller from the flight. The last caller cancels the flight, later calls start a new one.
+func (g *flightGroup) leave(key string, f *flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+
+	f.cancel()
+
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
@@ -0,0 +1,156 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitForWaiters waits until the call in flight for the key has the given number of waiting callers.
+func waitForWaiters(t *testing.T, c *Client, key string, waiters int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		c.flights.mu.Lock()
+		f := c.flights.flights[key]
+		done := f != nil && f.waiters == waiters
+		c.flights.mu.Unlock()
+
+		if done {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("call for %q did not reach %d waiters", key, waiters)
+}
+
+func Test_WithRequestCoalescing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	release := make(chan struct{})
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt"}})
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		server.serveSearch(w, r)
+	}
+
+	mockClient := server.client(t, WithRequestCoalescing())
+
+	const callers = 5
+
+	var (
+		wg      sync.WaitGroup
+		results [callers]*SearchGame
+		errs    [callers]error
+	)
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Differently spaced search terms are identical calls.
+			results[i], errs[i] = mockClient.Search(ctx, "the  Witcher", SearchModifierNone, nil)
+		}(i)
+	}
+
+	waitForWaiters(t, mockClient, searchCacheKey("the witcher", SearchModifierNone, mockClient.prepSearchRequest("", SearchModifierNone, nil)), callers)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil || results[i] != results[0] || len(results[i].Data) != 1 {
+			t.Fatalf("Search() caller %d = %+v, %v, want the shared result", i, results[i], errs[i])
+		}
+	}
+
+	if calls := server.searchCalls.Load(); calls != 1 {
+		t.Errorf("Search() hit the server %d times, want %d", calls, 1)
+	}
+
+	// Calls after the shared call finished send a new request.
+	if _, err := mockClient.Search(ctx, "the witcher", SearchModifierNone, nil); err != nil || server.searchCalls.Load() != 2 {
+		t.Errorf("Search() error = %v, search calls = %d", err, server.searchCalls.Load())
+	}
+}
+
+func Test_WithRequestCoalescing_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		release  = make(chan struct{})
+		canceled = make(chan struct{})
+	)
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt"}})
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		server.serveSearch(w, r)
+	}
+
+	mockClient := server.client(t, WithRequestCoalescing())
+	key := searchCacheKey("witcher", SearchModifierNone, mockClient.prepSearchRequest("", SearchModifierNone, nil))
+
+	firstCtx, cancelFirst := context.WithCancel(ctx)
+	firstErr := make(chan error, 1)
+
+	go func() {
+		_, err := mockClient.Search(firstCtx, "witcher", SearchModifierNone, nil)
+		firstErr <- err
+	}()
+
+	waitForWaiters(t, mockClient, key, 1)
+
+	second := make(chan *SearchGame, 1)
+
+	go func() {
+		result, _ := mockClient.Search(ctx, "witcher", SearchModifierNone, nil)
+		second <- result
+	}()
+
+	waitForWaiters(t, mockClient, key, 2)
+
+	// The first caller stops waiting, the request continues for the second caller.
+	cancelFirst()
+
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf(`Search() expected "%v" error, but received: %v`, context.Canceled, err)
+	}
+
+	close(release)
+
+	if result := <-second; result == nil || len(result.Data) != 1 {
+		t.Fatalf("Search() second caller = %+v, want the result of the shared request", result)
+	}
+
+	// Once the only caller stops waiting, the request is canceled.
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		// The server notices the closed connection once the body was read.
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+		close(canceled)
+	}
+
+	lastCtx, cancelLast := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelLast()
+
+	if _, err := mockClient.Search(lastCtx, "witcher", SearchModifierNone, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf(`Search() expected "%v" error, but received: %v`, context.DeadlineExceeded, err)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatalf("shared request was not canceled after the last caller left")
+	}
+}
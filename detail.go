@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 type (
@@ -229,6 +230,12 @@ type (
 		}
 		Page  string
 		Query GameDetailsQuery
+		// Raw is the __NEXT_DATA__ document of the game page, if enabled with WithRawPayloads.
+		Raw json.RawMessage `json:"-"`
+		// Stale is set if the result is served from the cache past its time to live, see WithStaleWhileRevalidate.
+		Stale bool `json:"-"`
+		// Age is the time since a cached result was fetched, 0 for fresh results.
+		Age time.Duration `json:"-"`
 	}
 
 	gameDetailsResponse struct {
@@ -246,23 +253,21 @@ type (
 		} `json:"props"`
 		Page  string           `json:"page"`
 		Query GameDetailsQuery `json:"query"`
+		raw   json.RawMessage
 	}
 )
 
 var GameIDRequiredErr = errors.New("gameID is required")
 
 func (c *Client) detailHTTPRequest(ctx context.Context, gameID int) (*http.Request, error) {
-	req, err := c.request(ctx, http.MethodGet, fmt.Sprintf("%s/%d", hltbGameURL, gameID), nil)
+	req, err := c.request(ctx, OperationDetail, http.MethodGet, fmt.Sprintf("%s/%d", c.url(hltbGamePath), gameID), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set(http.CanonicalHeaderKey("Accept"), "*/*")
-	req.Header.Set(http.CanonicalHeaderKey("Accept-Language"), "en")
 	req.Header.Set(http.CanonicalHeaderKey("Cache-Control"), "no-cache")
 	req.Header.Set(http.CanonicalHeaderKey("Pragma"), "no-cache")
-	req.Header.Set(http.CanonicalHeaderKey("Sec-Ch-Ua"), `Not;A Brand;v="99", "Google Chrome";v="115", "Chromium";v="115"`)
-	req.Header.Set(http.CanonicalHeaderKey("Sec-Ch-Ua-Mobile"), "?0")
 	req.Header.Set(http.CanonicalHeaderKey("Sec-Fetch-Mode"), "cors")
 	req.Header.Set(http.CanonicalHeaderKey("Sec-Fetch-Dest"), "empty")
 	req.Header.Set(http.CanonicalHeaderKey("Dnt"), "1")
@@ -273,11 +278,68 @@ func (c *Client) detailHTTPRequest(ctx context.Context, gameID int) (*http.Reque
 // Detail returns the details of a game by its HLTB ID.
 // If the context expires, the request will be canceled.
 // If the gameID is 0, an error will be returned.
-func (c *Client) Detail(ctx context.Context, gameID int) (*GameDetails, error) {
+func (c *Client) Detail(ctx context.Context, gameID int) (_ *GameDetails, err error) {
 	if gameID == 0 {
 		return nil, GameIDRequiredErr
 	}
 
+	cacheKey := detailCacheKey(gameID)
+
+	ctx, obs := c.observe(ctx, OperationDetail)
+	info := OperationInfo{GameID: gameID}
+
+	defer func() {
+		info.Err = err
+		obs.end(info)
+	}()
+
+	cached, age, state := c.cache.get(cacheKey)
+
+	switch state {
+	case cacheFresh, cacheRefresh:
+		if state == cacheRefresh {
+			c.revalidate(ctx, OperationDetail, cacheKey, OperationInfo{GameID: gameID}, func(ctx context.Context, _ *OperationInfo) error {
+				_, err := c.fetchDetail(ctx, gameID, cacheKey)
+				return err
+			})
+		}
+
+		info.CacheHit = true
+		return cached.(*GameDetails).fromCache(age, state == cacheRefresh), nil
+	}
+
+	details, err := c.fetchDetail(ctx, gameID, cacheKey)
+	if err != nil && c.serveStale(ctx, state, cacheKey, age, err) {
+		info.CacheHit = true
+		return cached.(*GameDetails).fromCache(age, true), nil
+	}
+
+	return details, err
+}
+
+// fromCache returns a copy of the cached details with their age.
+func (g *GameDetails) fromCache(age time.Duration, stale bool) *GameDetails {
+	details := *g
+	details.Age = age
+	details.Stale = stale
+
+	return &details
+}
+
+// fetchDetail requests the details of the game from HowLongToBeat, shared with identical calls in flight if enabled
+// with WithRequestCoalescing.
+func (c *Client) fetchDetail(ctx context.Context, gameID int, cacheKey string) (*GameDetails, error) {
+	value, err := c.flights.do(ctx, cacheKey, func(ctx context.Context) (any, error) {
+		return c.requestDetail(ctx, gameID, cacheKey)
+	})
+
+	details, _ := value.(*GameDetails)
+
+	return details, err
+}
+
+// requestDetail requests the details of the game from HowLongToBeat and caches them.
+func (c *Client) requestDetail(ctx context.Context, gameID int, cacheKey string) (*GameDetails, error) {
 	req, err := c.detailHTTPRequest(ctx, gameID)
 	if err != nil {
 		return nil, fmt.Errorf("create game details request: %w", err)
@@ -289,7 +351,14 @@ func (c *Client) Detail(ctx context.Context, gameID int) (*GameDetails, error) {
 		return nil, fmt.Errorf("execute game details request: %w", err)
 	}
 
-	return response.convertResponseToGameDetails()
+	details, err := response.convertResponseToGameDetails()
+	if err != nil {
+		return details, err
+	}
+
+	c.cache.set(cacheKey, details)
+
+	return details, nil
 }
 
 func (g *gameDetailsResponse) convertResponseToGameDetails() (*GameDetails, error) {
@@ -301,6 +370,7 @@ func (g *gameDetailsResponse) convertResponseToGameDetails() (*GameDetails, erro
 	data.Props.PageProps.PageMetadata = g.Props.PageProps.PageMetadata
 	data.Page = g.Page
 	data.Query = g.Query
+	data.Raw = g.raw
 
 	// Handle the special case IgnWikiNav field
 	var tempStruct GameDetailsIgnWikiNav
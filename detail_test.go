@@ -16,13 +16,13 @@ func Test_detailHTTPRequest(t *testing.T) {
 
 	var (
 		headers = map[string]string{
-			http.CanonicalHeaderKey("User-Agent"):       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/115.0.0.0 Safari/537.36",
+			http.CanonicalHeaderKey("User-Agent"):       DefaultHeaderProfile.UserAgent,
 			http.CanonicalHeaderKey("Accept"):           "*/*",
-			http.CanonicalHeaderKey("Accept-Language"):  "en",
+			http.CanonicalHeaderKey("Accept-Language"):  DefaultHeaderProfile.AcceptLanguage,
 			http.CanonicalHeaderKey("Cache-Control"):    "no-cache",
 			http.CanonicalHeaderKey("Pragma"):           "no-cache",
-			http.CanonicalHeaderKey("Sec-Ch-Ua"):        `Not;A Brand;v="99", "Google Chrome";v="115", "Chromium";v="115"`,
-			http.CanonicalHeaderKey("Sec-Ch-Ua-Mobile"): "?0",
+			http.CanonicalHeaderKey("Sec-Ch-Ua"):        DefaultHeaderProfile.SecChUa,
+			http.CanonicalHeaderKey("Sec-Ch-Ua-Mobile"): DefaultHeaderProfile.SecChUaMobile,
 			http.CanonicalHeaderKey("Sec-Fetch-Mode"):   "cors",
 			http.CanonicalHeaderKey("Sec-Fetch-Dest"):   "empty",
 			http.CanonicalHeaderKey("Dnt"):              "1",
@@ -40,8 +40,8 @@ func Test_detailHTTPRequest(t *testing.T) {
 		t.Fatalf("detailHTTPRequest() did not set the correct method: want: %s, received: %s", http.MethodGet, req.Method)
 	}
 
-	if req.URL.String() != fmt.Sprintf("%s/%d", hltbGameURL, gameID) {
-		t.Fatalf("detailHTTPRequest() did not set the correct URL: want: %s, received: %s", fmt.Sprintf("%s/%d", hltbGameURL, gameID), req.URL.String())
+	if req.URL.String() != fmt.Sprintf("%s/%d", hltbBaseURL+hltbGamePath, gameID) {
+		t.Fatalf("detailHTTPRequest() did not set the correct URL: want: %s, received: %s", fmt.Sprintf("%s/%d", hltbBaseURL+hltbGamePath, gameID), req.URL.String())
 	}
 
 	if req.Body != nil {
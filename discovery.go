@@ -0,0 +1,167 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+type (
+	// ScriptScan is the result of scanning a script of the homepage for the search endpoint.
+	ScriptScan struct {
+		Path string
+		// Found is set if the script contains the search endpoint.
+		Found bool
+		// Err is the error of fetching the script. Scripts without the search endpoint have no error.
+		Err error
+	}
+
+	// EndpointNotFoundError is returned if none of the scripts of the homepage contains the search endpoint.
+	EndpointNotFoundError struct {
+		Scanned []ScriptScan
+	}
+)
+
+// defaultDiscoveryConcurrency is the number of scripts fetched at once, if not set with WithDiscoveryConcurrency.
+const defaultDiscoveryConcurrency = 4
+
+var (
+	// EndpointNotFoundErr matches every EndpointNotFoundError with errors.Is.
+	EndpointNotFoundErr = errors.New("search endpoint not found")
+
+	// endpointFoundErr is the cause the scripts still in flight are canceled with once the search endpoint was found.
+	endpointFoundErr = errors.New("search endpoint found")
+)
+
+func (e *EndpointNotFoundError) Error() string {
+	var failed int
+	for _, scan := range e.Scanned {
+		if scan.Err != nil {
+			failed++
+		}
+	}
+
+	return fmt.Sprintf("%v in %d scripts, %d failed", EndpointNotFoundErr, len(e.Scanned), failed)
+}
+
+func (e *EndpointNotFoundError) Is(target error) bool {
+	return target == EndpointNotFoundErr
+}
+
+// WithDiscoveryConcurrency sets the number of scripts fetched at once while discovering the search endpoint.
+// The default is 4.
+func WithDiscoveryConcurrency(limit int) Option {
+	return func(client *Client) {
+		if limit > 0 {
+			client.discoveryConcurrency = limit
+		}
+	}
+}
+
+// scanScripts fetches the scripts concurrently until one of them contains the search endpoint. Scripts that fail are
+// skipped, only the end of ctx stops the scan early. It returns the endpoint along with the scanned scripts, in the
+// order they finished. Scripts canceled after the endpoint was found are not part of the scan and their errors are
+// not reported.
+func (c *Client) scanScripts(ctx context.Context, scriptPaths []string) (string, []ScriptScan, error) {
+	scanCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	limit := c.discoveryConcurrency
+	if limit <= 0 {
+		limit = defaultDiscoveryConcurrency
+	}
+
+	type scanResult struct {
+		scan     ScriptScan
+		endpoint string
+	}
+
+	var (
+		wg      sync.WaitGroup
+		paths   = make(chan string)
+		results = make(chan scanResult)
+	)
+
+	for i := 0; i < min(limit, len(scriptPaths)); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for path := range paths {
+				endpoint, err := c.scanScript(scanCtx, path)
+				results <- scanResult{scan: ScriptScan{Path: path, Found: endpoint != "", Err: err}, endpoint: endpoint}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(paths)
+
+		for _, path := range scriptPaths {
+			select {
+			case paths <- path:
+			case <-scanCtx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var (
+		endpoint string
+		scanned  []ScriptScan
+	)
+
+	for result := range results {
+		if scanCtx.Err() != nil && result.scan.Err != nil {
+			continue
+		}
+
+		scanned = append(scanned, result.scan)
+
+		if result.scan.Err != nil {
+			c.log(ctx, slog.LevelDebug, "failed to scan script for search endpoint", slog.String("script", result.scan.Path), slog.Any("error", result.scan.Err))
+		}
+
+		if result.endpoint != "" && endpoint == "" {
+			endpoint = result.endpoint
+			c.log(ctx, slog.LevelInfo, "discovered search endpoint", slog.String("endpoint", endpoint), slog.String("script", result.scan.Path))
+			cancel(endpointFoundErr)
+		}
+	}
+
+	if endpoint == "" && ctx.Err() != nil {
+		return "", scanned, fmt.Errorf("fetch endpoint: %w", ctx.Err())
+	}
+
+	return endpoint, scanned, nil
+}
+
+// scanStopped reports whether ctx belongs to a script fetch that was canceled because the search endpoint was found
+// in another script. Errors of these fetches are expected and not reported.
+func scanStopped(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), endpointFoundErr)
+}
+
+// scanScript fetches a script and returns the search endpoint it contains, or an empty string.
+func (c *Client) scanScript(ctx context.Context, path string) (string, error) {
+	req, err := c.endpointPathHTTPRequest(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("create endpoint request: %w", err)
+	}
+
+	var apiData ApiData
+
+	if err = c.do(req, c.endpointParser(&apiData)); err != nil {
+		return "", err
+	}
+
+	return apiData.endpointPath, nil
+}
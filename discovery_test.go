@@ -0,0 +1,213 @@
+package howlongtobeat
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newDiscoveryServer serves a homepage with the given chunk scripts in the order of their names. Each script is served
+// by its handler.
+func newDiscoveryServer(t *testing.T, scripts map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	names := make([]string, 0, len(scripts))
+	for name := range scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			for _, name := range names {
+				_, _ = fmt.Fprintf(w, `<script src="/_next/static/chunks/%s.js"></script>`, name)
+			}
+			return
+		}
+
+		handler, ok := scripts[strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/_next/static/chunks/"), ".js")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func serveScript(js string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(js))
+	}
+}
+
+func Test_discoverEndpointPath(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		inFlight, maxInFlight atomic.Int32
+		canceled              atomic.Int32
+	)
+
+	// blocked scripts only finish once their request is canceled.
+	blocked := func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		<-r.Context().Done()
+		canceled.Add(1)
+	}
+
+	scripts := map[string]http.HandlerFunc{
+		"1-failing": func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+		"2-other":   serveScript(`console.log("no endpoint")`),
+		"3-search": func(w http.ResponseWriter, r *http.Request) {
+			// The endpoint is only found once the other scripts are in flight.
+			time.Sleep(50 * time.Millisecond)
+			_, _ = w.Write([]byte(`fetch("/api/seek/abc",{method:"POST",body:e})`))
+		},
+	}
+	for i := 0; i < 6; i++ {
+		scripts[fmt.Sprintf("4-blocked%d", i)] = blocked
+	}
+
+	var (
+		observer = &recordingObserver{}
+		logs     bytes.Buffer
+		mu       sync.Mutex
+		failed   []string
+	)
+
+	reportErrors := Middleware{OnError: func(op Operation, req *http.Request, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, req.URL.Path)
+	}}
+
+	mockClient, err := New(
+		WithBaseURL(newDiscoveryServer(t, scripts).URL),
+		WithDiscoveryConcurrency(3),
+		WithObserver(observer),
+		WithMiddleware(reportErrors),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelWarn}))),
+	)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	var apiData ApiData
+	if err = mockClient.discoverEndpointPath(ctx, &apiData); err != nil || apiData.endpointPath != "/api/seek" {
+		t.Fatalf("discoverEndpointPath() = %q, %v, want %q", apiData.endpointPath, err, "/api/seek")
+	}
+
+	if maxInFlight.Load() > 3 {
+		t.Errorf("discoverEndpointPath() fetched %d scripts at once, want at most 3", maxInFlight.Load())
+	}
+
+	// Scripts in flight are canceled once the endpoint was found, and are not part of the scan.
+	deadline := time.Now().Add(time.Second)
+	for inFlight.Load() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if inFlight.Load() != 0 {
+		t.Errorf("discoverEndpointPath() left %d requests in flight", inFlight.Load())
+	}
+
+	// Only the failing script is reported, not the fetches canceled once the endpoint was found.
+	if len(failed) != 1 || !strings.Contains(failed[0], "failing") {
+		t.Errorf("OnError() called for %v, want only the failing script", failed)
+	}
+
+	if strings.Contains(logs.String(), "blocked") {
+		t.Errorf("canceled scripts were logged:\n%s", logs.String())
+	}
+
+	info := observer.ended[len(observer.ended)-1].info
+
+	var found bool
+	for _, scan := range info.Scripts {
+		switch {
+		case strings.Contains(scan.Path, "blocked"):
+			t.Errorf("scanned script %q was canceled", scan.Path)
+		case strings.Contains(scan.Path, "search"):
+			found = scan.Found && scan.Err == nil
+		case strings.Contains(scan.Path, "failing") && scan.Err == nil:
+			t.Errorf("scanned script %q has no error", scan.Path)
+		}
+	}
+
+	if !found {
+		t.Errorf("OperationInfo.Scripts = %+v, want the script with the endpoint", info.Scripts)
+	}
+}
+
+func Test_discoverEndpointPath_NotFound(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newDiscoveryServer(t, map[string]http.HandlerFunc{
+		"failing": func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) },
+		"a":       serveScript(`console.log("a")`),
+		"b":       serveScript(`console.log("b")`),
+	})
+
+	var failed atomic.Int32
+
+	mockClient, _ := New(WithBaseURL(server.URL), WithMiddleware(Middleware{OnError: func(op Operation, req *http.Request, err error) {
+		failed.Add(1)
+	}}))
+
+	var notFound *EndpointNotFoundError
+
+	err := mockClient.discoverEndpointPath(ctx, &ApiData{})
+	if !errors.As(err, &notFound) || !errors.Is(err, EndpointNotFoundErr) || len(notFound.Scanned) != 3 {
+		t.Fatalf(`discoverEndpointPath() expected "%v" error with 3 scanned scripts, but received: %v`, EndpointNotFoundErr, err)
+	}
+
+	if !strings.Contains(err.Error(), "3 scripts, 1 failed") {
+		t.Errorf("EndpointNotFoundError.Error() = %q", err.Error())
+	}
+
+	// Scripts without the endpoint are no errors.
+	if failed.Load() != 1 {
+		t.Errorf("OnError() called %d times, want 1 for the failing script", failed.Load())
+	}
+}
+
+func Test_discoverEndpointPath_Canceled(t *testing.T) {
+	blocked := func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}
+
+	server := newDiscoveryServer(t, map[string]http.HandlerFunc{"a": blocked, "b": blocked})
+	mockClient, _ := New(WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := mockClient.discoverEndpointPath(ctx, &ApiData{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf(`discoverEndpointPath() expected "%v" error, but received: %v`, context.DeadlineExceeded, err)
+	}
+}
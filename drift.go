@@ -0,0 +1,274 @@
+package howlongtobeat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type (
+	// PayloadKind is the kind of upstream payload a SchemaDrift was detected in.
+	PayloadKind string
+
+	// SchemaDrift lists the differences between an upstream payload and the structs it is decoded into.
+	// Paths are dot separated JSON keys, array elements are marked with [], e.g. "data[].game_name".
+	SchemaDrift struct {
+		Kind PayloadKind `json:"kind"`
+		URL  string      `json:"url,omitempty"`
+		// Unknown are the keys of the payload that are not modelled and therefore dropped while decoding.
+		Unknown []string `json:"unknown,omitempty"`
+		// Missing are the modelled keys that are absent from the payload and therefore decoded as zero values.
+		Missing []string `json:"missing,omitempty"`
+	}
+
+	// schemaField is a JSON key of a struct type.
+	schemaField struct {
+		name      string
+		typ       reflect.Type
+		omitEmpty bool
+	}
+
+	// schemaDiff collects the differences while walking a payload.
+	schemaDiff struct {
+		unknown map[string]bool
+		missing map[string]bool
+	}
+)
+
+const (
+	// PayloadSearch is the JSON response of the search endpoint.
+	PayloadSearch PayloadKind = "search"
+	// PayloadDetail is the __NEXT_DATA__ document of a game page.
+	PayloadDetail PayloadKind = "detail"
+)
+
+var UnknownPayloadKindErr = errors.New("unknown payload kind")
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// nextDataInternalKeys are keys Next.js adds to every __NEXT_DATA__ document, they are never reported as unknown.
+var nextDataInternalKeys = map[string]bool{
+	"buildId":               true,
+	"isFallback":            true,
+	"gssp":                  true,
+	"gsp":                   true,
+	"gip":                   true,
+	"appGip":                true,
+	"scriptLoader":          true,
+	"locale":                true,
+	"locales":               true,
+	"defaultLocale":         true,
+	"runtimeConfig":         true,
+	"customServer":          true,
+	"dynamicIds":            true,
+	"err":                   true,
+	"assetPrefix":           true,
+	"nextExport":            true,
+	"autoExport":            true,
+	"isPreview":             true,
+	"isExperimentalCompile": true,
+}
+
+// WithSchemaDrift enables the drift mode. Every search response and game page is compared to the structs it is
+// decoded into and hook is called for each response with unknown or missing fields.
+// The hook is called synchronously from the request, so it should return quickly.
+func WithSchemaDrift(hook func(drift SchemaDrift)) Option {
+	return func(client *Client) {
+		client.driftHook = hook
+	}
+}
+
+// DiffSchema compares a saved payload to the structs it would be decoded into.
+// For PayloadDetail, the payload may either be the __NEXT_DATA__ document or the whole HTML page of a game.
+func DiffSchema(kind PayloadKind, payload []byte) (*SchemaDrift, error) {
+	var schema any
+
+	switch kind {
+	case PayloadSearch:
+		schema = &SearchGame{}
+	case PayloadDetail:
+		schema = &gameDetailsResponse{}
+
+		if trimmed := bytes.TrimSpace(payload); len(trimmed) > 0 && trimmed[0] != '{' {
+			data, err := extractNextData(payload)
+			if err != nil {
+				return nil, err
+			}
+			payload = data
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", UnknownPayloadKindErr, kind)
+	}
+
+	return diffSchema(kind, payload, schema)
+}
+
+// HasDrift reports whether any unknown or missing fields were found.
+func (d *SchemaDrift) HasDrift() bool {
+	return len(d.Unknown) > 0 || len(d.Missing) > 0
+}
+
+// reportDrift compares the payload to the type of val and calls the drift hook if they differ.
+func (c *Client) reportDrift(resp *http.Response, payload []byte, val any) {
+	if c.driftHook == nil {
+		return
+	}
+
+	drift, err := diffSchema(payloadKindOf(val), payload, val)
+	if err != nil || !drift.HasDrift() {
+		return
+	}
+
+	if resp.Request != nil {
+		drift.URL = resp.Request.URL.String()
+	}
+	c.driftHook(*drift)
+}
+
+func payloadKindOf(val any) PayloadKind {
+	switch val.(type) {
+	case *SearchGame:
+		return PayloadSearch
+	case *gameDetailsResponse:
+		return PayloadDetail
+	default:
+		return PayloadKind(reflect.TypeOf(val).String())
+	}
+}
+
+func diffSchema(kind PayloadKind, payload []byte, schema any) (*SchemaDrift, error) {
+	var document any
+	if err := json.Unmarshal(payload, &document); err != nil {
+		return nil, err
+	}
+
+	diff := &schemaDiff{unknown: make(map[string]bool), missing: make(map[string]bool)}
+	diff.walk(reflect.TypeOf(schema), document, "")
+
+	return &SchemaDrift{
+		Kind:    kind,
+		Unknown: sortedKeys(diff.unknown),
+		Missing: sortedKeys(diff.missing),
+	}, nil
+}
+
+func (d *schemaDiff) walk(t reflect.Type, value any, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Raw messages are decoded by hand and have no fixed schema.
+	if t == rawMessageType {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+
+		fields := schemaFields(t)
+		present := make(map[string]bool, len(object))
+
+		for key, v := range object {
+			present[strings.ToLower(key)] = true
+
+			field, ok := fields[strings.ToLower(key)]
+			if !ok {
+				if !isInternalKey(path, key) {
+					d.unknown[joinPath(path, key)] = true
+				}
+				continue
+			}
+
+			d.walk(field.typ, v, joinPath(path, key))
+		}
+
+		for key, field := range fields {
+			if !present[key] && !field.omitEmpty {
+				d.missing[joinPath(path, field.name)] = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		elements, ok := value.([]any)
+		if !ok {
+			return
+		}
+
+		for _, element := range elements {
+			d.walk(t.Elem(), element, path+"[]")
+		}
+	}
+}
+
+// schemaFields returns the JSON keys of a struct type by their lower case name, like encoding/json matches them.
+func schemaFields(t reflect.Type) map[string]schemaField {
+	fields := make(map[string]schemaField, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for key, field := range schemaFields(f.Type) {
+				fields[key] = field
+			}
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[strings.ToLower(name)] = schemaField{
+			name:      name,
+			typ:       f.Type,
+			omitEmpty: strings.Contains(options, "omitempty"),
+		}
+	}
+
+	return fields
+}
+
+func isInternalKey(path, key string) bool {
+	return strings.HasPrefix(key, "__N_") || (path == "" && nextDataInternalKeys[key])
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
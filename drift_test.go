@@ -0,0 +1,96 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_DiffSchema_Detail(t *testing.T) {
+	page, err := os.ReadFile("test_files/test_html_parser.html")
+	if err != nil {
+		t.Fatalf("error reading HTML test file: %v", err)
+	}
+
+	drift, err := DiffSchema(PayloadDetail, page)
+	if err != nil {
+		t.Fatalf("DiffSchema() error = %v", err)
+	}
+
+	want := []string{
+		"props.pageProps.game.data.game[].count_discussion",
+		"props.pageProps.pageMetadata.noIndex",
+		"props.pageProps.related",
+	}
+
+	if !reflect.DeepEqual(drift.Unknown, want) {
+		t.Errorf("DiffSchema() unknown = %v, want %v", drift.Unknown, want)
+	}
+
+	if len(drift.Missing) != 0 {
+		t.Errorf("DiffSchema() missing = %v, want none", drift.Missing)
+	}
+}
+
+func Test_DiffSchema_Search(t *testing.T) {
+	payload := []byte(`{"color":"blue","title":"","category":"games","count":1,"pageCurrent":1,"pageTotal":1,"pageSize":20,
+		"data":[{"game_id":1,"game_name":"Game","game_rating":5}]}`)
+
+	drift, err := DiffSchema(PayloadSearch, payload)
+	if err != nil {
+		t.Fatalf("DiffSchema() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(drift.Unknown, []string{"data[].game_rating"}) {
+		t.Errorf("DiffSchema() unknown = %v", drift.Unknown)
+	}
+
+	// Similarity is computed locally and never expected upstream.
+	for _, missing := range drift.Missing {
+		if missing == "data[].Similarity" {
+			t.Errorf("DiffSchema() reported Similarity as missing")
+		}
+	}
+
+	if len(drift.Missing) == 0 || drift.Missing[0] != "data[].comp_100" {
+		t.Errorf("DiffSchema() missing = %v", drift.Missing)
+	}
+}
+
+func Test_DiffSchema_UnknownKind(t *testing.T) {
+	_, err := DiffSchema("user", []byte(`{}`))
+	if !errors.Is(err, UnknownPayloadKindErr) {
+		t.Fatalf(`DiffSchema() expected "%v" error, but received: %v`, UnknownPayloadKindErr, err)
+	}
+}
+
+func Test_WithSchemaDrift(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t)
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"color":"blue","title":"","category":"games","count":1,"pageCurrent":1,"pageTotal":1,"pageSize":20,"data":[],"facets":{}}`))
+	}
+
+	var drifts []SchemaDrift
+	mockClient := server.client(t, WithSchemaDrift(func(drift SchemaDrift) {
+		drifts = append(drifts, drift)
+	}))
+
+	if _, err := mockClient.Search(ctx, "Witcher", SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if len(drifts) != 1 {
+		t.Fatalf("WithSchemaDrift() reported %d drifts, want 1", len(drifts))
+	}
+
+	if drifts[0].Kind != PayloadSearch || !reflect.DeepEqual(drifts[0].Unknown, []string{"facets"}) || drifts[0].URL == "" {
+		t.Errorf("WithSchemaDrift() drift = %+v", drifts[0])
+	}
+}
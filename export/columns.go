@@ -0,0 +1,72 @@
+package export
+
+import (
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+var (
+	searchColumns = []column[howlongtobeat.SearchGameData]{
+		{"game_id", func(g howlongtobeat.SearchGameData) any { return g.GameID }},
+		{"game_name", func(g howlongtobeat.SearchGameData) any { return g.GameName }},
+		{"game_alias", func(g howlongtobeat.SearchGameData) any { return g.GameAlias }},
+		{"game_type", func(g howlongtobeat.SearchGameData) any { return g.GameType }},
+		{"game_image", func(g howlongtobeat.SearchGameData) any { return g.GameImage }},
+		{"profile_dev", func(g howlongtobeat.SearchGameData) any { return g.ProfileDev }},
+		{"profile_platform", func(g howlongtobeat.SearchGameData) any { return g.ProfilePlatform }},
+		{"profile_steam", func(g howlongtobeat.SearchGameData) any { return g.ProfileSteam }},
+		{"release_world", func(g howlongtobeat.SearchGameData) any { return g.ReleaseWorld }},
+		{"review_score", func(g howlongtobeat.SearchGameData) any { return g.ReviewScore }},
+		{"comp_main", func(g howlongtobeat.SearchGameData) any { return seconds(g.CompMain) }},
+		{"comp_plus", func(g howlongtobeat.SearchGameData) any { return seconds(g.CompPlus) }},
+		{"comp_100", func(g howlongtobeat.SearchGameData) any { return seconds(g.Comp100) }},
+		{"comp_all", func(g howlongtobeat.SearchGameData) any { return seconds(g.CompAll) }},
+		{"comp_all_count", func(g howlongtobeat.SearchGameData) any { return g.CompAllCount }},
+		{"similarity", func(g howlongtobeat.SearchGameData) any { return g.Similarity }},
+	}
+
+	searchDefaultColumns = []string{"game_id", "game_name", "release_world", "comp_main", "comp_plus", "comp_100", "similarity"}
+
+	searchSimpleColumns = []column[*howlongtobeat.SearchGameSimple]{
+		{"game_id", func(g *howlongtobeat.SearchGameSimple) any { return g.GameID }},
+		{"game_name", func(g *howlongtobeat.SearchGameSimple) any { return g.GameName }},
+		{"profile_platform", func(g *howlongtobeat.SearchGameSimple) any { return g.ProfilePlatform }},
+		{"game_image", func(g *howlongtobeat.SearchGameSimple) any { return g.GameImage }},
+		{"comp_main", func(g *howlongtobeat.SearchGameSimple) any { return hours(g.CompMain) }},
+		{"comp_plus", func(g *howlongtobeat.SearchGameSimple) any { return hours(g.CompPlus) }},
+		{"comp_all", func(g *howlongtobeat.SearchGameSimple) any { return hours(g.CompAll) }},
+		{"similarity", func(g *howlongtobeat.SearchGameSimple) any { return g.Similarity }},
+	}
+
+	detailColumns = []column[howlongtobeat.GameDetailsGameDataGame]{
+		{"game_id", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.GameID }},
+		{"game_name", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.GameName }},
+		{"game_alias", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.GameAlias }},
+		{"game_type", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.GameType }},
+		{"game_image", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.GameImage }},
+		{"game_parent", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.GameParent }},
+		{"profile_dev", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.ProfileDev }},
+		{"profile_pub", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.ProfilePub }},
+		{"profile_platform", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.ProfilePlatform }},
+		{"profile_genre", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.ProfileGenre }},
+		{"profile_steam", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.ProfileSteam }},
+		{"release_world", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.ReleaseWorld }},
+		{"review_score", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.ReviewScore }},
+		{"count_comp", func(g howlongtobeat.GameDetailsGameDataGame) any { return g.CountComp }},
+		{"comp_main", func(g howlongtobeat.GameDetailsGameDataGame) any { return seconds(g.CompMain) }},
+		{"comp_plus", func(g howlongtobeat.GameDetailsGameDataGame) any { return seconds(g.CompPlus) }},
+		{"comp_100", func(g howlongtobeat.GameDetailsGameDataGame) any { return seconds(g.Comp100) }},
+		{"comp_all", func(g howlongtobeat.GameDetailsGameDataGame) any { return seconds(g.CompAll) }},
+	}
+
+	detailDefaultColumns = []string{"game_id", "game_name", "profile_platform", "release_world", "comp_main", "comp_plus", "comp_100", "comp_all"}
+
+	detailSimpleColumns = []column[*howlongtobeat.GameDetailSimple]{
+		{"game_id", func(g *howlongtobeat.GameDetailSimple) any { return g.GameID }},
+		{"game_name", func(g *howlongtobeat.GameDetailSimple) any { return g.GameName }},
+		{"profile_platform", func(g *howlongtobeat.GameDetailSimple) any { return g.ProfilePlatform }},
+		{"game_image", func(g *howlongtobeat.GameDetailSimple) any { return g.GameImage }},
+		{"comp_main", func(g *howlongtobeat.GameDetailSimple) any { return hours(g.CompMain) }},
+		{"comp_plus", func(g *howlongtobeat.GameDetailSimple) any { return hours(g.CompPlus) }},
+		{"comp_all", func(g *howlongtobeat.GameDetailSimple) any { return hours(g.CompAll) }},
+	}
+)
@@ -0,0 +1,322 @@
+// Package export encodes search and detail results as CSV, newline-delimited JSON, Markdown or plain-text tables.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+type (
+	// Format is the output format of an Encoder.
+	Format string
+
+	// Encoder writes results to an output stream in the configured Format.
+	Encoder struct {
+		w       io.Writer
+		format  Format
+		columns []string
+		header  bool
+	}
+
+	// Option is a type alias for functions to configure your Encoder.
+	Option func(encoder *Encoder)
+
+	// column is a named value of a single row of type T.
+	column[T any] struct {
+		name  string
+		value func(row T) any
+	}
+
+	// seconds is a duration in seconds, as used by the raw HowLongToBeat data.
+	seconds int
+	// hours is a duration in hours, as used by the simplified data.
+	hours float64
+)
+
+const (
+	FormatCSV      Format = "csv"
+	FormatNDJSON   Format = "ndjson"
+	FormatMarkdown Format = "markdown"
+	FormatText     Format = "text"
+)
+
+var (
+	UnknownFormatErr = errors.New("unknown format")
+	UnknownColumnErr = errors.New("unknown column")
+)
+
+// WithColumns selects the columns and their order. The available column names match the JSON tags of the encoded
+// structs, e.g. "game_id", "game_name" or "comp_main".
+func WithColumns(columns ...string) Option {
+	return func(encoder *Encoder) {
+		encoder.columns = columns
+	}
+}
+
+// WithoutHeader omits the header row of CSV and plain-text tables. Markdown tables always have a header.
+func WithoutHeader() Option {
+	return func(encoder *Encoder) {
+		encoder.header = false
+	}
+}
+
+// NewEncoder creates a new Encoder writing to w in the given format.
+func NewEncoder(w io.Writer, format Format, options ...Option) *Encoder {
+	e := &Encoder{
+		w:      w,
+		format: format,
+		header: true,
+	}
+
+	for _, opt := range options {
+		opt(e)
+	}
+
+	return e
+}
+
+// EncodeSearch writes one row per game of the search result.
+func (e *Encoder) EncodeSearch(s *howlongtobeat.SearchGame) error {
+	return encode(e, searchColumns, searchDefaultColumns, s.Data)
+}
+
+// EncodeSearchSimple writes one row per simplified search result.
+func (e *Encoder) EncodeSearchSimple(s []*howlongtobeat.SearchGameSimple) error {
+	return encode(e, searchSimpleColumns, nil, s)
+}
+
+// EncodeDetails writes a single row with the game data of the details.
+func (e *Encoder) EncodeDetails(d *howlongtobeat.GameDetails) error {
+	return encode(e, detailColumns, detailDefaultColumns, d.Props.PageProps.Game.Data.Game)
+}
+
+// EncodeDetailSimple writes a single row with the simplified game details.
+func (e *Encoder) EncodeDetailSimple(d *howlongtobeat.GameDetailSimple) error {
+	return encode(e, detailSimpleColumns, nil, []*howlongtobeat.GameDetailSimple{d})
+}
+
+// HumanDuration formats a duration as hours and minutes, e.g. "51h 23m". Durations of zero, which HowLongToBeat uses
+// for missing data, are formatted as "-".
+func HumanDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int((d % time.Hour).Minutes()))
+	}
+}
+
+func encode[T any](e *Encoder, available []column[T], defaults []string, rows []T) error {
+	columns, err := selectColumns(available, e.columnNames(defaults))
+	if err != nil {
+		return err
+	}
+
+	switch e.format {
+	case FormatCSV:
+		return encodeCSV(e, columns, rows)
+	case FormatNDJSON:
+		return encodeNDJSON(e, columns, rows)
+	case FormatMarkdown:
+		return encodeMarkdown(e, columns, rows)
+	case FormatText:
+		return encodeText(e, columns, rows)
+	default:
+		return fmt.Errorf("%w: %q", UnknownFormatErr, e.format)
+	}
+}
+
+func (e *Encoder) columnNames(defaults []string) []string {
+	if len(e.columns) > 0 {
+		return e.columns
+	}
+
+	return defaults
+}
+
+// selectColumns returns the columns with the given names, or all available columns if no names are given.
+func selectColumns[T any](available []column[T], names []string) ([]column[T], error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+
+	selected := make([]column[T], 0, len(names))
+
+	for _, name := range names {
+		found := false
+
+		for _, c := range available {
+			if c.name == strings.TrimSpace(name) {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: %q", UnknownColumnErr, name)
+		}
+	}
+
+	return selected, nil
+}
+
+func encodeCSV[T any](e *Encoder, columns []column[T], rows []T) error {
+	w := csv.NewWriter(e.w)
+
+	if e.header {
+		if err := w.Write(columnHeader(columns)); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, c := range columns {
+			record[i] = formatRaw(c.value(row))
+		}
+
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+func encodeNDJSON[T any](e *Encoder, columns []column[T], rows []T) error {
+	for _, row := range rows {
+		// Build the object by hand to keep the column order.
+		var sb strings.Builder
+		sb.WriteByte('{')
+
+		for i, c := range columns {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+
+			key, _ := json.Marshal(c.name)
+			value, err := json.Marshal(c.value(row))
+			if err != nil {
+				return err
+			}
+
+			sb.Write(key)
+			sb.WriteByte(':')
+			sb.Write(value)
+		}
+
+		sb.WriteString("}\n")
+
+		if _, err := io.WriteString(e.w, sb.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodeMarkdown[T any](e *Encoder, columns []column[T], rows []T) error {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+
+	var sb strings.Builder
+
+	sb.WriteString("|")
+	for _, name := range columnHeader(columns) {
+		sb.WriteString(" " + name + " |")
+	}
+
+	sb.WriteString("\n|")
+	for range columns {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("\n")
+
+	for _, row := range rows {
+		sb.WriteString("|")
+		for _, c := range columns {
+			sb.WriteString(" " + escape.Replace(formatHuman(c.value(row))) + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(e.w, sb.String())
+
+	return err
+}
+
+func encodeText[T any](e *Encoder, columns []column[T], rows []T) error {
+	w := tabwriter.NewWriter(e.w, 0, 0, 2, ' ', 0)
+
+	if e.header {
+		if _, err := fmt.Fprintln(w, strings.Join(columnHeader(columns), "\t")); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		values := make([]string, len(columns))
+		for i, c := range columns {
+			values[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(formatHuman(c.value(row)))
+		}
+
+		if _, err := fmt.Fprintln(w, strings.Join(values, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+func columnHeader[T any](columns []column[T]) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+
+	return names
+}
+
+// formatRaw formats a value for machine-readable output.
+func formatRaw(value any) string {
+	switch v := value.(type) {
+	case seconds:
+		return strconv.Itoa(int(v))
+	case hours:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatHuman formats a value for tables read by humans.
+func formatHuman(value any) string {
+	switch v := value.(type) {
+	case seconds:
+		return HumanDuration(time.Duration(v) * time.Second)
+	case hours:
+		return HumanDuration(time.Duration(math.Round(float64(v)*60)) * time.Minute)
+	default:
+		return formatRaw(v)
+	}
+}
@@ -0,0 +1,118 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+var testSearch = &howlongtobeat.SearchGame{
+	Data: []howlongtobeat.SearchGameData{
+		{GameID: 10270, GameName: "The Witcher 3: Wild Hunt", ReleaseWorld: 2015, CompMain: 185696, CompPlus: 371370, Comp100: 624261, Similarity: 0.33},
+		{GameID: 107256, GameName: "New Quest | Contract", CompMain: 1086, Similarity: 0.1},
+	},
+}
+
+func TestHumanDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{0, "-"},
+		{18 * time.Minute, "18m"},
+		{2 * time.Hour, "2h"},
+		{51*time.Hour + 34*time.Minute + 56*time.Second, "51h 35m"},
+	}
+
+	for _, tt := range tests {
+		if got := HumanDuration(tt.duration); got != tt.want {
+			t.Errorf("HumanDuration(%v) = %q, want %q", tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestEncoder_EncodeSearch_CSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := NewEncoder(&buf, FormatCSV, WithColumns("game_id", "game_name", "comp_main")).EncodeSearch(testSearch); err != nil {
+		t.Fatalf("EncodeSearch() error = %v", err)
+	}
+
+	want := "game_id,game_name,comp_main\n10270,The Witcher 3: Wild Hunt,185696\n107256,New Quest | Contract,1086\n"
+	if buf.String() != want {
+		t.Errorf("EncodeSearch() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoder_EncodeSearch_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := NewEncoder(&buf, FormatNDJSON, WithColumns("game_id", "similarity")).EncodeSearch(testSearch); err != nil {
+		t.Fatalf("EncodeSearch() error = %v", err)
+	}
+
+	want := "{\"game_id\":10270,\"similarity\":0.33}\n{\"game_id\":107256,\"similarity\":0.1}\n"
+	if buf.String() != want {
+		t.Errorf("EncodeSearch() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoder_EncodeSearch_Markdown(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := NewEncoder(&buf, FormatMarkdown, WithColumns("game_name", "comp_main", "comp_100")).EncodeSearch(testSearch); err != nil {
+		t.Fatalf("EncodeSearch() error = %v", err)
+	}
+
+	want := "| game_name | comp_main | comp_100 |\n|---|---|---|\n" +
+		"| The Witcher 3: Wild Hunt | 51h 35m | 173h 24m |\n" +
+		"| New Quest \\| Contract | 18m | - |\n"
+	if buf.String() != want {
+		t.Errorf("EncodeSearch() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoder_EncodeSearchSimple_Text(t *testing.T) {
+	var buf bytes.Buffer
+
+	simple := []*howlongtobeat.SearchGameSimple{{GameID: 10270, GameName: "The Witcher 3", CompMain: 52}}
+
+	if err := NewEncoder(&buf, FormatText, WithColumns("game_id", "game_name", "comp_main"), WithoutHeader()).EncodeSearchSimple(simple); err != nil {
+		t.Fatalf("EncodeSearchSimple() error = %v", err)
+	}
+
+	if want := "10270  The Witcher 3  52h\n"; buf.String() != want {
+		t.Errorf("EncodeSearchSimple() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoder_EncodeDetails(t *testing.T) {
+	var (
+		buf     bytes.Buffer
+		details howlongtobeat.GameDetails
+	)
+
+	details.Props.PageProps.Game.Data.Game = []howlongtobeat.GameDetailsGameDataGame{{GameID: 10270, GameName: "The Witcher 3", CompMain: 3600}}
+
+	if err := NewEncoder(&buf, FormatCSV).EncodeDetails(&details); err != nil {
+		t.Fatalf("EncodeDetails() error = %v", err)
+	}
+
+	want := "game_id,game_name,profile_platform,release_world,comp_main,comp_plus,comp_100,comp_all\n10270,The Witcher 3,,,3600,0,0,0\n"
+	if buf.String() != want {
+		t.Errorf("EncodeDetails() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoder_Errors(t *testing.T) {
+	if err := NewEncoder(&bytes.Buffer{}, FormatCSV, WithColumns("unknown")).EncodeSearch(testSearch); !errors.Is(err, UnknownColumnErr) {
+		t.Errorf("EncodeSearch() expected %v, received: %v", UnknownColumnErr, err)
+	}
+
+	if err := NewEncoder(&bytes.Buffer{}, "xml").EncodeSearch(testSearch); !errors.Is(err, UnknownFormatErr) {
+		t.Errorf("EncodeSearch() expected %v, received: %v", UnknownFormatErr, err)
+	}
+}
@@ -0,0 +1,95 @@
+package howlongtobeat
+
+import (
+	"net/http"
+)
+
+// HeaderProfile is a coherent set of browser headers sent with every request: the User-Agent, the matching client hints
+// and the Accept-Language header. Client hints are only sent by Chromium based browsers, leave them empty for others.
+type HeaderProfile struct {
+	Name            string
+	UserAgent       string
+	SecChUa         string
+	SecChUaMobile   string
+	SecChUaPlatform string
+	AcceptLanguage  string
+}
+
+var (
+	HeaderProfileChrome = HeaderProfile{
+		Name:            "chrome",
+		UserAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36",
+		SecChUa:         `"Chromium";v="140", "Not=A?Brand";v="24", "Google Chrome";v="140"`,
+		SecChUaMobile:   "?0",
+		SecChUaPlatform: `"Windows"`,
+		AcceptLanguage:  "en-US,en;q=0.9",
+	}
+
+	HeaderProfileEdge = HeaderProfile{
+		Name:            "edge",
+		UserAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 Safari/537.36 Edg/140.0.0.0",
+		SecChUa:         `"Chromium";v="140", "Not=A?Brand";v="24", "Microsoft Edge";v="140"`,
+		SecChUaMobile:   "?0",
+		SecChUaPlatform: `"Windows"`,
+		AcceptLanguage:  "en-US,en;q=0.9",
+	}
+
+	HeaderProfileFirefox = HeaderProfile{
+		Name:           "firefox",
+		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:143.0) Gecko/20100101 Firefox/143.0",
+		AcceptLanguage: "en-US,en;q=0.5",
+	}
+
+	HeaderProfileSafari = HeaderProfile{
+		Name:           "safari",
+		UserAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.6 Safari/605.1.15",
+		AcceptLanguage: "en-US,en;q=0.9",
+	}
+
+	// DefaultHeaderProfile is used unless WithHeaderProfile or WithHeaderProfileRotation is set.
+	DefaultHeaderProfile = HeaderProfileChrome
+)
+
+// WithHeaderProfile sets the browser headers sent with every request, e.g. HeaderProfileFirefox or a custom profile.
+func WithHeaderProfile(profile HeaderProfile) Option {
+	return func(client *Client) {
+		client.headerProfiles = []HeaderProfile{profile}
+	}
+}
+
+// WithHeaderProfileRotation rotates through the given profiles, using the next profile for every request.
+// All requests of a search, including the token fetch, may use different profiles.
+func WithHeaderProfileRotation(profiles ...HeaderProfile) Option {
+	return func(client *Client) {
+		if len(profiles) > 0 {
+			client.headerProfiles = append([]HeaderProfile(nil), profiles...)
+		}
+	}
+}
+
+// headerProfile returns the profile for the next request.
+func (c *Client) headerProfile() HeaderProfile {
+	switch len(c.headerProfiles) {
+	case 0:
+		return DefaultHeaderProfile
+	case 1:
+		return c.headerProfiles[0]
+	default:
+		return c.headerProfiles[(c.headerIndex.Add(1)-1)%uint64(len(c.headerProfiles))]
+	}
+}
+
+// apply sets the headers of the profile on the request. Empty headers are not sent.
+func (p HeaderProfile) apply(req *http.Request) {
+	for header, value := range map[string]string{
+		"User-Agent":         p.UserAgent,
+		"Sec-Ch-Ua":          p.SecChUa,
+		"Sec-Ch-Ua-Mobile":   p.SecChUaMobile,
+		"Sec-Ch-Ua-Platform": p.SecChUaPlatform,
+		"Accept-Language":    p.AcceptLanguage,
+	} {
+		if value != "" {
+			req.Header.Set(header, value)
+		}
+	}
+}
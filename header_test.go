@@ -0,0 +1,62 @@
+package howlongtobeat
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func Test_WithHeaderProfile(t *testing.T) {
+	mockClient, err := New(WithHeaderProfile(HeaderProfileFirefox))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, build := range []func() (*http.Request, error){
+		func() (*http.Request, error) { return mockClient.tokenHTTPRequest(context.Background()) },
+		func() (*http.Request, error) {
+			return mockClient.searchHTTPRequest(context.Background(), nil, hltbSearchEndpoint, "")
+		},
+		func() (*http.Request, error) { return mockClient.detailHTTPRequest(context.Background(), 1) },
+	} {
+		req, err := build()
+		if err != nil {
+			t.Fatalf("request error = %v", err)
+		}
+
+		if got := req.Header.Get("User-Agent"); got != HeaderProfileFirefox.UserAgent {
+			t.Errorf("%s User-Agent = %q, want %q", req.URL.Path, got, HeaderProfileFirefox.UserAgent)
+		}
+
+		if got := req.Header.Get("Accept-Language"); got != HeaderProfileFirefox.AcceptLanguage {
+			t.Errorf("%s Accept-Language = %q, want %q", req.URL.Path, got, HeaderProfileFirefox.AcceptLanguage)
+		}
+
+		// Firefox does not send client hints.
+		if got := req.Header.Get("Sec-Ch-Ua"); got != "" {
+			t.Errorf("%s Sec-Ch-Ua = %q, want none", req.URL.Path, got)
+		}
+	}
+}
+
+func Test_WithHeaderProfileRotation(t *testing.T) {
+	custom := HeaderProfile{Name: "custom", UserAgent: "custom-agent/1.0"}
+
+	mockClient, err := New(WithHeaderProfileRotation(HeaderProfileChrome, custom))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	want := []string{HeaderProfileChrome.UserAgent, custom.UserAgent, HeaderProfileChrome.UserAgent}
+
+	for i, userAgent := range want {
+		req, err := mockClient.detailHTTPRequest(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("detailHTTPRequest() error = %v", err)
+		}
+
+		if got := req.Header.Get("User-Agent"); got != userAgent {
+			t.Errorf("request %d User-Agent = %q, want %q", i, got, userAgent)
+		}
+	}
+}
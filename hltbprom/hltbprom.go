@@ -0,0 +1,321 @@
+// Package hltbprom collects Prometheus metrics of a howlongtobeat.Client and the REST server without depending on the
+// Prometheus client library. The Collector serves the metrics in the Prometheus text exposition format.
+//
+// Metrics:
+//
+//	hltb_client_operation_duration_seconds  histogram of operations by operation
+//	hltb_client_errors_total                failed operations by operation and error kind
+//	hltb_client_retries_total               retries by operation
+//	hltb_client_cache_hits_total            searches and details served from the cache
+//	hltb_client_cache_misses_total          searches and details not served from the cache
+//	hltb_client_token_refreshes_total       tokens fetched to replace a rejected token
+//	hltb_server_rate_limit_wait_seconds     histogram of the time rejected clients have to wait
+//	hltb_server_rate_limited_requests_total requests rejected by the rate limiter
+package hltbprom
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+type (
+	// Option configures a Collector.
+	Option func(collector *Collector)
+
+	// Collector collects the metrics of a Client and a Server. Use it with howlongtobeat.WithObserver and
+	// server.WithRateLimitHook and serve it as an http.Handler, e.g. on /metrics.
+	Collector struct {
+		mu      sync.Mutex
+		buckets []float64
+
+		durations      map[string]*histogram
+		errors         map[errorLabels]uint64
+		retries        map[string]uint64
+		cacheHits      map[string]uint64
+		cacheMisses    map[string]uint64
+		tokenRefreshes uint64
+		rateLimitWait  *histogram
+		rateLimited    uint64
+	}
+
+	histogram struct {
+		counts []uint64
+		sum    float64
+		count  uint64
+	}
+
+	errorLabels struct {
+		operation string
+		kind      string
+	}
+
+	sample struct {
+		labels []string
+		value  float64
+	}
+
+	// countingWriter counts the written bytes and keeps the first error.
+	countingWriter struct {
+		w   *bufio.Writer
+		n   int64
+		err error
+	}
+)
+
+// DefaultBuckets are the default histogram buckets in seconds, the same as the Prometheus client defaults.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// WithBuckets sets the upper bounds of the histogram buckets in seconds.
+func WithBuckets(buckets ...float64) Option {
+	return func(collector *Collector) {
+		if len(buckets) > 0 {
+			collector.buckets = append([]float64(nil), buckets...)
+			sort.Float64s(collector.buckets)
+		}
+	}
+}
+
+// NewCollector creates a new Collector.
+func NewCollector(options ...Option) *Collector {
+	c := &Collector{
+		buckets:     DefaultBuckets,
+		durations:   make(map[string]*histogram),
+		errors:      make(map[errorLabels]uint64),
+		retries:     make(map[string]uint64),
+		cacheHits:   make(map[string]uint64),
+		cacheMisses: make(map[string]uint64),
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	c.rateLimitWait = c.newHistogram()
+
+	return c
+}
+
+// OperationStart implements howlongtobeat.Observer.
+func (c *Collector) OperationStart(ctx context.Context, _ howlongtobeat.Operation) context.Context {
+	return ctx
+}
+
+// OperationEnd implements howlongtobeat.Observer.
+func (c *Collector) OperationEnd(_ context.Context, op howlongtobeat.Operation, info howlongtobeat.OperationInfo) {
+	operation := string(op)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	duration, ok := c.durations[operation]
+	if !ok {
+		duration = c.newHistogram()
+		c.durations[operation] = duration
+	}
+	c.observe(duration, info.Duration.Seconds())
+
+	if info.Err != nil {
+		c.errors[errorLabels{operation: operation, kind: howlongtobeat.ErrorKind(info.Err)}]++
+	}
+
+	if info.Retries > 0 {
+		c.retries[operation] += uint64(info.Retries)
+	}
+
+	if op == howlongtobeat.OperationSearch || op == howlongtobeat.OperationDetail {
+		if info.CacheHit {
+			c.cacheHits[operation]++
+		} else {
+			c.cacheMisses[operation]++
+		}
+	}
+
+	if info.Refresh {
+		c.tokenRefreshes++
+	}
+}
+
+// ObserveRateLimit records a decision of the server's rate limiter. wait is the time until the client may send its
+// next request, it is only recorded for rejected requests. Use it with server.WithRateLimitHook.
+func (c *Collector) ObserveRateLimit(allowed bool, wait time.Duration) {
+	if allowed {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.observe(c.rateLimitWait, wait.Seconds())
+	c.rateLimited++
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	_, _ = c.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format to w.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	c.writeHistograms(cw, "hltb_client_operation_duration_seconds", "Duration of HowLongToBeat client operations.", c.durations)
+	writeCounters(cw, "hltb_client_errors_total", "Failed HowLongToBeat client operations.", errorSamples(c.errors))
+	writeCounters(cw, "hltb_client_retries_total", "Retries of HowLongToBeat client operations.", operationSamples(c.retries))
+	writeCounters(cw, "hltb_client_cache_hits_total", "Searches and details served from the cache.", operationSamples(c.cacheHits))
+	writeCounters(cw, "hltb_client_cache_misses_total", "Searches and details not served from the cache.", operationSamples(c.cacheMisses))
+	writeCounters(cw, "hltb_client_token_refreshes_total", "Auth tokens fetched to replace a rejected token.", []sample{{value: float64(c.tokenRefreshes)}})
+	c.writeHistograms(cw, "hltb_server_rate_limit_wait_seconds", "Time rejected clients have to wait before the rate limiter allows their next request.", map[string]*histogram{"": c.rateLimitWait})
+	writeCounters(cw, "hltb_server_rate_limited_requests_total", "Requests rejected by the rate limiter.", []sample{{value: float64(c.rateLimited)}})
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+
+	return cw.n, cw.err
+}
+
+func (c *Collector) newHistogram() *histogram {
+	return &histogram{counts: make([]uint64, len(c.buckets))}
+}
+
+func (c *Collector) observe(h *histogram, value float64) {
+	for i, upperBound := range c.buckets {
+		if value <= upperBound {
+			h.counts[i]++
+		}
+	}
+
+	h.sum += value
+	h.count++
+}
+
+// writeHistograms writes one histogram per operation. The empty operation writes a histogram without labels.
+func (c *Collector) writeHistograms(w *countingWriter, name, help string, histograms map[string]*histogram) {
+	w.printf("# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+
+	for _, operation := range sortedKeys(histograms) {
+		h := histograms[operation]
+
+		var labels []string
+		if operation != "" {
+			labels = []string{"operation", operation}
+		}
+
+		for i, upperBound := range c.buckets {
+			w.printf("%s_bucket%s %d\n", name, formatLabels(append(labels, "le", formatFloat(upperBound))...), h.counts[i])
+		}
+
+		w.printf("%s_bucket%s %d\n", name, formatLabels(append(labels, "le", "+Inf")...), h.count)
+		w.printf("%s_sum%s %s\n", name, formatLabels(labels...), formatFloat(h.sum))
+		w.printf("%s_count%s %d\n", name, formatLabels(labels...), h.count)
+	}
+}
+
+func writeCounters(w *countingWriter, name, help string, samples []sample) {
+	w.printf("# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+
+	for _, s := range samples {
+		w.printf("%s%s %s\n", name, formatLabels(s.labels...), formatFloat(s.value))
+	}
+}
+
+func operationSamples(counters map[string]uint64) []sample {
+	samples := make([]sample, 0, len(counters))
+
+	for _, operation := range sortedKeys(counters) {
+		samples = append(samples, sample{labels: []string{"operation", operation}, value: float64(counters[operation])})
+	}
+
+	return samples
+}
+
+func errorSamples(counters map[errorLabels]uint64) []sample {
+	keys := make([]errorLabels, 0, len(counters))
+	for key := range counters {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].operation != keys[j].operation {
+			return keys[i].operation < keys[j].operation
+		}
+		return keys[i].kind < keys[j].kind
+	})
+
+	samples := make([]sample, len(keys))
+	for i, key := range keys {
+		samples[i] = sample{labels: []string{"operation", key.operation, "kind", key.kind}, value: float64(counters[key])}
+	}
+
+	return samples
+}
+
+// formatLabels formats label name and value pairs, e.g. {operation="search"}.
+func formatLabels(pairs ...string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelValueEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (w *countingWriter) printf(format string, args ...any) {
+	if w.err != nil {
+		return
+	}
+
+	n, err := fmt.Fprintf(w.w, format, args...)
+	w.n += int64(n)
+	w.err = err
+}
@@ -0,0 +1,95 @@
+package hltbprom_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+	"github.com/forbiddencoding/howlongtobeat/hltbprom"
+	"github.com/forbiddencoding/howlongtobeat/hltbtest"
+)
+
+func TestCollector(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := hltbtest.NewServer()
+	defer server.Close()
+	server.AddGame(howlongtobeat.GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt"})
+
+	collector := hltbprom.NewCollector(hltbprom.WithBuckets(1, 10))
+
+	client, err := server.NewClient(howlongtobeat.WithObserver(collector), howlongtobeat.WithCache(time.Minute, 10), howlongtobeat.WithSearchRetry())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err = client.Search(ctx, "Witcher", howlongtobeat.SearchModifierNone, nil); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+	}
+
+	server.RotateToken("rotated")
+
+	if _, err = client.Search(ctx, "Witcher 3", howlongtobeat.SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	server.InjectFault(hltbtest.EndpointDetail, hltbtest.Fault{StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+	if _, err = client.Detail(ctx, 10270); err == nil {
+		t.Fatalf("Detail() expected error")
+	}
+
+	collector.ObserveRateLimit(true, 0)
+	collector.ObserveRateLimit(false, 2*time.Second)
+
+	var out strings.Builder
+	if _, err = collector.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"# TYPE hltb_client_operation_duration_seconds histogram\n",
+		`hltb_client_operation_duration_seconds_bucket{operation="search",le="+Inf"} 3` + "\n",
+		`hltb_client_operation_duration_seconds_count{operation="token"} 2` + "\n",
+		`hltb_client_errors_total{operation="detail",kind="status"} 1` + "\n",
+		`hltb_client_retries_total{operation="search"} 1` + "\n",
+		`hltb_client_cache_hits_total{operation="search"} 1` + "\n",
+		`hltb_client_cache_misses_total{operation="search"} 2` + "\n",
+		`hltb_client_cache_misses_total{operation="detail"} 1` + "\n",
+		"hltb_client_token_refreshes_total 1\n",
+		// Allowed requests have no wait time.
+		`hltb_server_rate_limit_wait_seconds_bucket{le="1"} 0` + "\n",
+		`hltb_server_rate_limit_wait_seconds_bucket{le="10"} 1` + "\n",
+		"hltb_server_rate_limit_wait_seconds_sum 2\n",
+		"hltb_server_rate_limit_wait_seconds_count 1\n",
+		"hltb_server_rate_limited_requests_total 1\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("WriteTo() output does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	collector := hltbprom.NewCollector()
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	body, _ := io.ReadAll(rec.Body)
+	if !strings.Contains(string(body), "hltb_client_token_refreshes_total 0\n") {
+		t.Errorf("ServeHTTP() body = %s", body)
+	}
+}
@@ -0,0 +1,473 @@
+// Package hltbtest provides an in-process fake HowLongToBeat server for testing code that uses the howlongtobeat
+// package.
+//
+// The server serves the token endpoint, the finder search endpoint, the homepage with its _next chunk scripts and the
+// game detail pages from an in-memory catalog. Faults like error status codes, slow or malformed responses can be
+// injected per endpoint.
+package hltbtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+type (
+	// Endpoint identifies one of the endpoints served by the Server.
+	Endpoint string
+
+	// Fault describes a failure injected into the responses of an endpoint.
+	Fault struct {
+		// StatusCode is returned instead of the regular response, e.g. 403 or 429.
+		StatusCode int
+		// Delay delays the response, e.g. to trigger client timeouts.
+		Delay time.Duration
+		// Malformed returns a broken body, e.g. truncated JSON or HTML without the __NEXT_DATA__ script.
+		Malformed bool
+		// Times limits the fault to the given number of requests. If 0, the fault applies until ClearFaults is called.
+		Times int
+	}
+
+	// Server is a fake HowLongToBeat server.
+	Server struct {
+		*httptest.Server
+
+		mu           sync.Mutex
+		token        string
+		games        map[int]*game
+		faults       map[Endpoint]*Fault
+		requests     map[Endpoint]int
+		searchBodies []SearchRequest
+	}
+
+	// SearchRequest is the body of a search request received by the Server.
+	SearchRequest struct {
+		SearchType    string   `json:"searchType"`
+		SearchTerms   []string `json:"searchTerms"`
+		SearchPage    int      `json:"searchPage"`
+		Size          int      `json:"size"`
+		SearchOptions struct {
+			Games struct {
+				Modifier string `json:"modifier"`
+			} `json:"games"`
+		} `json:"searchOptions"`
+	}
+
+	game struct {
+		data          howlongtobeat.GameDetailsGameDataGame
+		relationships []howlongtobeat.GameDetailsGameDataRelationships
+	}
+)
+
+const (
+	EndpointToken  Endpoint = "token"
+	EndpointSearch Endpoint = "search"
+	EndpointHome   Endpoint = "home"
+	EndpointScript Endpoint = "script"
+	EndpointDetail Endpoint = "detail"
+)
+
+const (
+	// SearchPath is the path of the finder search endpoint.
+	SearchPath = "/api/finder"
+	// Token is the auth token handed out by the token endpoint.
+	Token = "hltbtest-token"
+	// ScriptPath is the path of the _next chunk script containing the search endpoint.
+	ScriptPath = "/_next/static/chunks/pages/_app-hltbtest.js"
+	// emptyScriptPath is a chunk script without the search endpoint, served before ScriptPath.
+	emptyScriptPath = "/_next/static/chunks/framework-hltbtest.js"
+)
+
+// NewServer starts a new fake server with an empty catalog. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		token:    Token,
+		games:    make(map[int]*game),
+		faults:   make(map[Endpoint]*Fault),
+		requests: make(map[Endpoint]int),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// NewClient returns a howlongtobeat.Client talking to the server. Options are applied after the base URL and the
+// HTTP client of the server.
+func (s *Server) NewClient(options ...howlongtobeat.Option) (*howlongtobeat.Client, error) {
+	return howlongtobeat.New(append([]howlongtobeat.Option{
+		howlongtobeat.WithHTTPClient(s.Client()),
+		howlongtobeat.WithBaseURL(s.URL),
+	}, options...)...)
+}
+
+// AddGame adds a game and its related content like DLCs to the catalog, replacing any game with the same ID.
+func (s *Server) AddGame(data howlongtobeat.GameDetailsGameDataGame, relationships ...howlongtobeat.GameDetailsGameDataRelationships) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.games[data.GameID] = &game{data: data, relationships: relationships}
+}
+
+// RemoveGame removes a game from the catalog.
+func (s *Server) RemoveGame(gameID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.games, gameID)
+}
+
+// InjectFault makes the endpoint fail as described by fault, replacing any previous fault of the endpoint.
+func (s *Server) InjectFault(endpoint Endpoint, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults[endpoint] = &fault
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = make(map[Endpoint]*Fault)
+}
+
+// RotateToken changes the token handed out by the token endpoint. Search requests with the previous token are
+// rejected with 403 Forbidden.
+func (s *Server) RotateToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = token
+}
+
+// Requests returns the number of requests received by the endpoint.
+func (s *Server) Requests(endpoint Endpoint) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[endpoint]
+}
+
+// SearchRequests returns the bodies of all search requests received so far.
+func (s *Server) SearchRequests() []SearchRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]SearchRequest(nil), s.searchBodies...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := "/" + strings.TrimLeft(r.URL.Path, "/")
+
+	var (
+		endpoint Endpoint
+		handler  func(w http.ResponseWriter, r *http.Request, malformed bool)
+	)
+
+	switch {
+	case path == "/api/finder/init" && r.Method == http.MethodGet:
+		endpoint, handler = EndpointToken, s.serveToken
+	case path == SearchPath && r.Method == http.MethodPost:
+		endpoint, handler = EndpointSearch, s.serveSearch
+	case path == "/" && r.Method == http.MethodGet:
+		endpoint, handler = EndpointHome, s.serveHome
+	case strings.HasPrefix(path, "/_next/static/chunks/") && r.Method == http.MethodGet:
+		endpoint, handler = EndpointScript, s.serveScript
+	case strings.HasPrefix(path, "/game/") && r.Method == http.MethodGet:
+		endpoint, handler = EndpointDetail, s.serveDetail
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	fault := s.takeFault(endpoint)
+
+	if fault.Delay > 0 {
+		select {
+		case <-time.After(fault.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fault.StatusCode != 0 {
+		w.WriteHeader(fault.StatusCode)
+		return
+	}
+
+	handler(w, r, fault.Malformed)
+}
+
+// takeFault counts the request and returns the fault to apply to it, if any.
+func (s *Server) takeFault(endpoint Endpoint) Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[endpoint]++
+
+	fault, ok := s.faults[endpoint]
+	if !ok {
+		return Fault{}
+	}
+
+	if fault.Times > 0 {
+		fault.Times--
+		if fault.Times == 0 {
+			delete(s.faults, endpoint)
+		}
+	}
+
+	return *fault
+}
+
+func (s *Server) serveToken(w http.ResponseWriter, _ *http.Request, malformed bool) {
+	if malformed {
+		_, _ = w.Write([]byte(`{"token":`))
+		return
+	}
+
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+
+	writeJSON(w, howlongtobeat.TokenResponse{Token: token})
+}
+
+func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request, malformed bool) {
+	var req SearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.searchBodies = append(s.searchBodies, req)
+	validToken := r.Header.Get("x-auth-token") == s.token
+	results := s.search(req)
+	s.mu.Unlock()
+
+	if !validToken {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if malformed {
+		_, _ = w.Write([]byte(`{"data":[{"game_id":`))
+		return
+	}
+
+	page, size := req.SearchPage, req.Size
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 20
+	}
+
+	resp := howlongtobeat.SearchGame{
+		Color:       "blue",
+		Category:    "games",
+		Count:       len(results),
+		PageCurrent: page,
+		PageTotal:   (len(results) + size - 1) / size,
+		PageSize:    size,
+		Data:        []howlongtobeat.SearchGameData{},
+	}
+
+	if start := (page - 1) * size; start < len(results) {
+		resp.Data = results[start:min(start+size, len(results))]
+	}
+
+	writeJSON(w, resp)
+}
+
+// search returns all games matching every search term and the modifier, ordered by ID.
+func (s *Server) search(req SearchRequest) []howlongtobeat.SearchGameData {
+	ids := make([]int, 0, len(s.games))
+	for id := range s.games {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	var results []howlongtobeat.SearchGameData
+
+	for _, id := range ids {
+		g := s.games[id].data
+
+		switch req.SearchOptions.Games.Modifier {
+		case howlongtobeat.SearchModifierOnlyDLC:
+			if g.GameType != "dlc" {
+				continue
+			}
+		case howlongtobeat.SearchModifierHideDLC:
+			if g.GameType == "dlc" {
+				continue
+			}
+		}
+
+		if !matchesTerms(g, req.SearchTerms) {
+			continue
+		}
+
+		results = append(results, searchData(g))
+	}
+
+	return results
+}
+
+func (s *Server) serveHome(w http.ResponseWriter, _ *http.Request, malformed bool) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if malformed {
+		_, _ = fmt.Fprint(w, `<!DOCTYPE html><html><head><title>HowLongToBeat</title></head><body></body></html>`)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, `<!DOCTYPE html><html><head><title>HowLongToBeat</title>`+
+		`<script src="%s" defer=""></script><script src="%s" defer=""></script>`+
+		`</head><body></body></html>`, emptyScriptPath, ScriptPath)
+}
+
+func (s *Server) serveScript(w http.ResponseWriter, r *http.Request, malformed bool) {
+	w.Header().Set("Content-Type", "application/javascript")
+
+	if malformed || r.URL.Path != ScriptPath {
+		_, _ = fmt.Fprint(w, `(self.webpackChunk_N_E=self.webpackChunk_N_E||[]).push([[1],{}]);`)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, `(self.webpackChunk_N_E=self.webpackChunk_N_E||[]).push([[2],{1:function(e){`+
+		`let t=await fetch("%s",{method:"POST",body:JSON.stringify(e)});return t.json()}}]);`, SearchPath)
+}
+
+func (s *Server) serveDetail(w http.ResponseWriter, r *http.Request, malformed bool) {
+	gameID, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/game/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	g, ok := s.games[gameID]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if malformed {
+		_, _ = fmt.Fprintf(w, `<!DOCTYPE html><html><head><title>%s</title></head><body></body></html>`, g.data.GameName)
+		return
+	}
+
+	nextData := map[string]any{
+		"props": map[string]any{
+			"pageProps": map[string]any{
+				"game": map[string]any{
+					"count": 1,
+					"data": map[string]any{
+						"game":          []howlongtobeat.GameDetailsGameDataGame{g.data},
+						"individuality": []any{},
+						"relationships": append([]howlongtobeat.GameDetailsGameDataRelationships{}, g.relationships...),
+						"userReviews":   map[string]any{"review_count": 0},
+						"platformData":  []any{},
+					},
+				},
+				"ignWikiSlug": "",
+				"ignMap":      nil,
+				"ignWikiNav":  []any{},
+				"pageMetadata": map[string]any{
+					"title":     fmt.Sprintf("How long is %s? | HowLongToBeat", g.data.GameName),
+					"canonical": fmt.Sprintf("https://howlongtobeat.com/game/%d", gameID),
+					"template":  "game",
+				},
+			},
+		},
+		"page":  "/game/[gameId]",
+		"query": map[string]string{"gameId": strconv.Itoa(gameID)},
+	}
+
+	data, err := json.Marshal(nextData)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, `<!DOCTYPE html><html><head><title>%s</title></head><body><div id="__next"></div>`+
+		`<script id="__NEXT_DATA__" type="application/json">%s</script></body></html>`, g.data.GameName, data)
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func matchesTerms(g howlongtobeat.GameDetailsGameDataGame, terms []string) bool {
+	name := strings.ToLower(g.GameName + " " + g.GameAlias)
+
+	for _, term := range terms {
+		if !strings.Contains(name, strings.ToLower(term)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// searchData converts the detail data of a game to the data returned by the search endpoint.
+func searchData(g howlongtobeat.GameDetailsGameDataGame) howlongtobeat.SearchGameData {
+	year, _ := strconv.Atoi(strings.SplitN(g.ReleaseWorld, "-", 2)[0])
+
+	return howlongtobeat.SearchGameData{
+		Count:           1,
+		GameID:          g.GameID,
+		GameName:        g.GameName,
+		GameNameDate:    g.GameNameDate,
+		GameAlias:       g.GameAlias,
+		GameType:        g.GameType,
+		GameImage:       g.GameImage,
+		CompLvlCombine:  g.CompLvlCombine,
+		CompLvlSp:       g.CompLvlSp,
+		CompLvlCo:       g.CompLvlCo,
+		CompLvlMp:       g.CompLvlMp,
+		CompLvlSpd:      g.CompLvlSpd,
+		CompMain:        g.CompMain,
+		CompPlus:        g.CompPlus,
+		Comp100:         g.Comp100,
+		CompAll:         g.CompAll,
+		CompMainCount:   g.CompMainCount,
+		CompPlusCount:   g.CompPlusCount,
+		Comp100Count:    g.Comp100Count,
+		CompAllCount:    g.CompAllCount,
+		InvestedCo:      g.InvestedCo,
+		InvestedMp:      g.InvestedMp,
+		InvestedCoCount: g.InvestedCoCount,
+		InvestedMpCount: g.InvestedMpCount,
+		CountComp:       g.CountComp,
+		CountSpeedrun:   g.CompSpeedCount,
+		CountBacklog:    g.CountBacklog,
+		CountReview:     g.CountReview,
+		ReviewScore:     g.ReviewScore,
+		CountPLaying:    g.CountPlaying,
+		CountRetired:    g.CountRetired,
+		ProfileDev:      g.ProfileDev,
+		ProfileSteam:    g.ProfileSteam,
+		ProfilePlatform: g.ProfilePlatform,
+		ReleaseWorld:    year,
+	}
+}
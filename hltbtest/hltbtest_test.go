@@ -0,0 +1,170 @@
+package hltbtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+	"github.com/forbiddencoding/howlongtobeat/hltbtest"
+)
+
+func newServer(t *testing.T) (*hltbtest.Server, *howlongtobeat.Client) {
+	t.Helper()
+
+	server := hltbtest.NewServer()
+	t.Cleanup(server.Close)
+
+	dlc := howlongtobeat.GameDetailsGameDataRelationships{GameID: 21, GameName: "Hearts of Stone", GameType: "dlc", CompMain: 36000}
+
+	server.AddGame(howlongtobeat.GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt", GameType: "game", CompMain: 185696, ReleaseWorld: "2015-05-19"}, dlc)
+	server.AddGame(howlongtobeat.GameDetailsGameDataGame{GameID: 21, GameName: "The Witcher 3: Wild Hunt - Hearts of Stone", GameType: "dlc", GameParent: 10270, CompMain: 36000})
+	server.AddGame(howlongtobeat.GameDetailsGameDataGame{GameID: 3, GameName: "Elden Ring", GameType: "game"})
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	return server, client
+}
+
+func ExampleServer() {
+	server := hltbtest.NewServer()
+	defer server.Close()
+
+	server.AddGame(howlongtobeat.GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt"})
+
+	client, err := server.NewClient()
+	if err != nil {
+		panic(err)
+	}
+
+	_, _ = client.Search(context.Background(), "Witcher", howlongtobeat.SearchModifierNone, nil)
+}
+
+func TestServer_Search(t *testing.T) {
+	server, client := newServer(t)
+
+	result, err := client.Search(context.Background(), "Witcher 3", howlongtobeat.SearchModifierHideDLC, nil)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if len(result.Data) != 1 || result.Data[0].GameID != 10270 || result.Data[0].ReleaseWorld != 2015 {
+		t.Fatalf("Search() = %+v", result.Data)
+	}
+
+	result, err = client.Search(context.Background(), "Witcher", howlongtobeat.SearchModifierNone, &howlongtobeat.SearchOptions{
+		Pagination: &howlongtobeat.SearchGamePagination{Page: 2, PageSize: 1},
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if len(result.Data) != 1 || result.Data[0].GameID != 10270 || result.PageTotal != 2 {
+		t.Fatalf("Search() page 2 = %+v", result)
+	}
+
+	if requests := server.SearchRequests(); len(requests) != 2 || requests[0].SearchOptions.Games.Modifier != "hide_dlc" {
+		t.Errorf("SearchRequests() = %+v", requests)
+	}
+
+	if n := server.Requests(hltbtest.EndpointToken); n != 1 {
+		t.Errorf("Requests(token) = %d, want %d", n, 1)
+	}
+}
+
+func TestServer_Detail(t *testing.T) {
+	_, client := newServer(t)
+
+	bundle, err := client.DetailBundle(context.Background(), 21, nil)
+	if err != nil {
+		t.Fatalf("DetailBundle() error = %v", err)
+	}
+
+	if bundle.CompMain != 185696+36000 {
+		t.Errorf("DetailBundle() comp_main = %d", bundle.CompMain)
+	}
+
+	var statusErr *howlongtobeat.StatusError
+	if _, err = client.Detail(context.Background(), 404); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Detail() expected 404, received: %v", err)
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	server, client := newServer(t)
+
+	server.InjectFault(hltbtest.EndpointDetail, hltbtest.Fault{StatusCode: http.StatusTooManyRequests, Times: 1})
+
+	var statusErr *howlongtobeat.StatusError
+	if _, err := client.Detail(context.Background(), 10270); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Detail() expected 429, received: %v", err)
+	}
+
+	if _, err := client.Detail(context.Background(), 10270); err != nil {
+		t.Fatalf("Detail() after fault error = %v", err)
+	}
+
+	server.InjectFault(hltbtest.EndpointDetail, hltbtest.Fault{Malformed: true})
+
+	if _, err := client.Detail(context.Background(), 10270); err == nil {
+		t.Fatalf("Detail() expected error for malformed page")
+	}
+
+	server.InjectFault(hltbtest.EndpointDetail, hltbtest.Fault{Delay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Detail(ctx, 10270); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Detail() expected deadline exceeded, received: %v", err)
+	}
+
+	server.ClearFaults()
+	server.InjectFault(hltbtest.EndpointSearch, hltbtest.Fault{StatusCode: http.StatusForbidden})
+
+	if _, err := client.Search(context.Background(), "Witcher", howlongtobeat.SearchModifierNone, nil); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("Search() expected 403, received: %v", err)
+	}
+}
+
+func TestServer_RotateToken(t *testing.T) {
+	server, client := newServer(t)
+
+	server.RotateToken("rotated")
+
+	var statusErr *howlongtobeat.StatusError
+	if _, err := client.Search(context.Background(), "Witcher", howlongtobeat.SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	server.RotateToken("rotated-again")
+
+	if _, err := client.Search(context.Background(), "Elden", howlongtobeat.SearchModifierNone, nil); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("Search() with stale token expected 403, received: %v", err)
+	}
+}
+
+func TestServer_EndpointDiscovery(t *testing.T) {
+	server, _ := newServer(t)
+
+	client, err := server.NewClient(howlongtobeat.WithSearchRetry())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	server.InjectFault(hltbtest.EndpointSearch, hltbtest.Fault{StatusCode: http.StatusNotFound, Times: 1})
+
+	// The rejected default endpoint is discovered from the chunk scripts of the homepage.
+	if _, err = client.Search(context.Background(), "Witcher", howlongtobeat.SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if home, scripts := server.Requests(hltbtest.EndpointHome), server.Requests(hltbtest.EndpointScript); home != 1 || scripts != 2 {
+		t.Errorf("Requests() home = %d, scripts = %d, want 1 and 2", home, scripts)
+	}
+}
@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type (
+	csvReader struct {
+		r       *csv.Reader
+		header  []string
+		columns map[string]int
+	}
+
+	csvWriter struct {
+		w      *csv.Writer
+		header bool
+	}
+)
+
+func newCSVReader(r io.Reader) *csvReader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	return &csvReader{r: reader}
+}
+
+func (c *csvReader) Read() (*Record, error) {
+	if c.columns == nil {
+		header, err := c.r.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		c.header = header
+		c.columns = make(map[string]int)
+		precedences := make(map[string]int)
+
+		for i, name := range header {
+			kind, precedence := columnKind(name)
+			if kind == "" {
+				continue
+			}
+
+			if p, ok := precedences[kind]; !ok || precedence < p {
+				c.columns[kind] = i
+				precedences[kind] = precedence
+			}
+		}
+
+		if _, ok := c.columns["title"]; !ok {
+			return nil, MissingTitleColumnErr
+		}
+	}
+
+	values, err := c.r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	// Pad short rows, so the appended result columns line up with the header.
+	for len(values) < len(c.header) {
+		values = append(values, "")
+	}
+
+	record := &Record{
+		Title:    c.value(values, "title"),
+		Platform: c.value(values, "platform"),
+		header:   c.header,
+		values:   values,
+	}
+	record.Year, _ = strconv.Atoi(c.value(values, "year"))
+
+	return record, nil
+}
+
+func (c *csvReader) value(values []string, kind string) string {
+	i, ok := c.columns[kind]
+	if !ok || i >= len(values) {
+		return ""
+	}
+
+	return strings.TrimSpace(values[i])
+}
+
+func newCSVWriter(w io.Writer, header bool) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w), header: header}
+}
+
+func (c *csvWriter) Write(record *Record, result Result) error {
+	if c.header {
+		c.header = false
+		if err := c.w.Write(append(append([]string{}, record.header...), resultColumns...)); err != nil {
+			return err
+		}
+	}
+
+	row := append(append([]string{}, record.values...),
+		strconv.Itoa(result.GameID),
+		result.GameName,
+		strconv.FormatFloat(result.Similarity, 'f', -1, 64),
+		strconv.FormatFloat(result.CompMain, 'f', -1, 64),
+		strconv.FormatFloat(result.CompPlus, 'f', -1, 64),
+		strconv.FormatFloat(result.Comp100, 'f', -1, 64),
+		strconv.FormatBool(result.Confident),
+	)
+
+	if err := c.w.Write(row); err != nil {
+		return err
+	}
+
+	// Flush every record, so a partial output can be resumed.
+	c.w.Flush()
+
+	return c.w.Error()
+}
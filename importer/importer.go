@@ -0,0 +1,222 @@
+// Package importer resolves lists of game titles, e.g. spreadsheets exported as CSV or JSON lines, to HowLongToBeat
+// games and writes the enriched records back.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+type (
+	// Format is the file format of the input and output of Run.
+	Format string
+
+	// Record is a single title read from the input. Platform and Year are optional hints used to pick the best match.
+	Record struct {
+		Title    string
+		Platform string
+		Year     int
+
+		// header, values and object keep the original record, so it can be written back unchanged.
+		header []string
+		values []string
+		object map[string]any
+	}
+
+	// Result is the HowLongToBeat game a Record was resolved to. Times are in hours.
+	Result struct {
+		GameID     int     `json:"game_id"`
+		GameName   string  `json:"game_name"`
+		Similarity float64 `json:"similarity"`
+		CompMain   float64 `json:"comp_main"`
+		CompPlus   float64 `json:"comp_plus"`
+		Comp100    float64 `json:"comp_100"`
+		// Confident is true if the match is similar enough to the title and agrees with the platform and year hints.
+		Confident bool `json:"confident"`
+	}
+
+	recordReader interface {
+		Read() (*Record, error)
+	}
+
+	resultWriter interface {
+		Write(record *Record, result Result) error
+	}
+)
+
+const (
+	FormatCSV        Format = "csv"
+	FormatJSONLines  Format = "jsonl"
+	ConfidenceCutoff        = 0.8
+	// hintBonus is added to the similarity of a candidate for each matching platform or year hint.
+	hintBonus = 0.1
+)
+
+var (
+	MissingTitleColumnErr = errors.New("input has no title column")
+	UnknownFormatErr      = errors.New("unknown format")
+)
+
+// resultColumns are the columns appended to each record of the output.
+var resultColumns = []string{"game_id", "game_name", "similarity", "comp_main", "comp_plus", "comp_100", "confident"}
+
+// Run reads all records from in, resolves them and writes the enriched records to out.
+// The first skip records are neither resolved nor written, which allows resuming from a partial output by passing
+// the result of Completed as skip and appending to the existing output. The CSV header is only written if skip is 0.
+// Run returns the number of records written. If resolving a record fails, Run stops and returns the error, the
+// output contains all records up to the failed one.
+func Run(ctx context.Context, client *howlongtobeat.Client, format Format, in io.Reader, out io.Writer, skip int) (int, error) {
+	reader, writer, err := newReadWriter(format, in, out, skip == 0)
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+
+	for i := 0; ; i++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return written, nil
+		}
+		if err != nil {
+			return written, fmt.Errorf("read record %d: %w", i+1, err)
+		}
+
+		if i < skip {
+			continue
+		}
+
+		result, err := Resolve(ctx, client, record)
+		if err != nil {
+			return written, fmt.Errorf("resolve %q: %w", record.Title, err)
+		}
+
+		if err = writer.Write(record, result); err != nil {
+			return written, fmt.Errorf("write record %d: %w", i+1, err)
+		}
+
+		written++
+	}
+}
+
+// Completed returns the number of records in a partial output previously written by Run.
+func Completed(format Format, out io.Reader) (int, error) {
+	reader, _, err := newReadWriter(format, out, io.Discard, false)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+
+	for {
+		_, err = reader.Read()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		count++
+	}
+}
+
+// Resolve searches for the title of the record and picks the most similar result, preferring results that match the
+// platform and year hints. If no game is found, the zero Result is returned.
+func Resolve(ctx context.Context, client *howlongtobeat.Client, record *Record) (Result, error) {
+	if strings.TrimSpace(record.Title) == "" {
+		return Result{}, nil
+	}
+
+	search, err := client.Search(ctx, record.Title, howlongtobeat.SearchModifierNone, nil)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var (
+		best      *howlongtobeat.SearchGameData
+		bestScore = -1.0
+		bestHints bool
+	)
+
+	for i := range search.Data {
+		game := &search.Data[i]
+
+		score := game.Similarity
+		hints := true
+
+		if record.Platform != "" {
+			if strings.Contains(strings.ToLower(game.ProfilePlatform), strings.ToLower(record.Platform)) {
+				score += hintBonus
+			} else {
+				hints = false
+			}
+		}
+
+		if record.Year != 0 {
+			if game.ReleaseWorld == record.Year {
+				score += hintBonus
+			} else {
+				hints = false
+			}
+		}
+
+		if score > bestScore {
+			best, bestScore, bestHints = game, score, hints
+		}
+	}
+
+	if best == nil {
+		return Result{}, nil
+	}
+
+	return Result{
+		GameID:     best.GameID,
+		GameName:   best.GameName,
+		Similarity: best.Similarity,
+		CompMain:   math.Round(float64(best.CompMain) / 3600),
+		CompPlus:   math.Round(float64(best.CompPlus) / 3600),
+		Comp100:    math.Round(float64(best.Comp100) / 3600),
+		Confident:  best.Similarity >= ConfidenceCutoff && bestHints,
+	}, nil
+}
+
+func newReadWriter(format Format, in io.Reader, out io.Writer, header bool) (recordReader, resultWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(in), newCSVWriter(out, header), nil
+	case FormatJSONLines:
+		return newJSONLinesReader(in), newJSONLinesWriter(out), nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", UnknownFormatErr, format)
+	}
+}
+
+// columnKind maps a column or key name of the input to the Record field it is read into. If the input has more than
+// one name for the same field, the name with the lowest precedence is read, e.g. title before name before game.
+func columnKind(name string) (kind string, precedence int) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "title":
+		return "title", 0
+	case "name":
+		return "title", 1
+	case "game":
+		return "title", 2
+	case "game_title":
+		return "title", 3
+	case "platform":
+		return "platform", 0
+	case "year":
+		return "year", 0
+	case "release_year":
+		return "year", 1
+	default:
+		return "", 0
+	}
+}
@@ -0,0 +1,206 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (r *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestClient(t *testing.T) (*howlongtobeat.Client, *int) {
+	t.Helper()
+
+	searches := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/init") {
+			_, _ = w.Write([]byte(`{"token":"token"}`))
+			return
+		}
+
+		searches++
+
+		var result howlongtobeat.SearchGame
+
+		var body struct {
+			SearchTerms []string `json:"searchTerms"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		if strings.Join(body.SearchTerms, " ") == "Prey" {
+			result.Data = []howlongtobeat.SearchGameData{
+				{GameID: 1, GameName: "Prey", ProfilePlatform: "PC, Xbox 360", ReleaseWorld: 2006, CompMain: 36000},
+				{GameID: 2, GameName: "Prey", ProfilePlatform: "PC, PlayStation 4", ReleaseWorld: 2017, CompMain: 61200, CompPlus: 86400, Comp100: 129600},
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	t.Cleanup(server.Close)
+
+	target, _ := url.Parse(server.URL)
+
+	client, err := howlongtobeat.New(howlongtobeat.WithHTTPClient(&http.Client{Transport: &rewriteTransport{target: target}}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return client, &searches
+}
+
+func TestRun_CSV(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, _ := newTestClient(t)
+
+	in := "Title,Platform,Year,Notes\nPrey,PlayStation 4,2017,replay\nUnknown Game,,,\n"
+
+	var out bytes.Buffer
+
+	written, err := Run(ctx, client, FormatCSV, strings.NewReader(in), &out, 0)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if written != 2 {
+		t.Fatalf("Run() written = %d, want %d", written, 2)
+	}
+
+	want := "Title,Platform,Year,Notes,game_id,game_name,similarity,comp_main,comp_plus,comp_100,confident\n" +
+		"Prey,PlayStation 4,2017,replay,2,Prey,1,17,24,36,true\n" +
+		"Unknown Game,,,,0,,0,0,0,0,false\n"
+
+	if out.String() != want {
+		t.Errorf("Run() output =\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRun_Resume(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, searches := newTestClient(t)
+
+	in := "name\nPrey\nPrey\nPrey\n"
+	out := bytes.NewBufferString("name,game_id,game_name,similarity,comp_main,comp_plus,comp_100,confident\nPrey,2,Prey,1,17,24,36,true\n")
+
+	completed, err := Completed(FormatCSV, bytes.NewReader(out.Bytes()))
+	if err != nil {
+		t.Fatalf("Completed() error = %v", err)
+	}
+
+	if completed != 1 {
+		t.Fatalf("Completed() = %d, want %d", completed, 1)
+	}
+
+	written, err := Run(ctx, client, FormatCSV, strings.NewReader(in), out, completed)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if written != 2 || *searches != 2 {
+		t.Fatalf("Run() written = %d, searches = %d, want 2 each", written, *searches)
+	}
+
+	if lines := strings.Count(out.String(), "\n"); lines != 4 {
+		t.Errorf("Run() output has %d lines, want %d:\n%s", lines, 4, out.String())
+	}
+}
+
+func TestRun_JSONLines(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, _ := newTestClient(t)
+
+	in := `{"title":"Prey","year":2006,"id":"a-1"}` + "\n\n" + `{"title":"Prey","year":"2017","platform":"Switch"}` + "\n"
+
+	var out bytes.Buffer
+
+	if _, err := Run(ctx, client, FormatJSONLines, strings.NewReader(in), &out, 0); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Run() output = %q, want 2 lines", out.String())
+	}
+
+	var first, second map[string]any
+	_ = json.Unmarshal([]byte(lines[0]), &first)
+	_ = json.Unmarshal([]byte(lines[1]), &second)
+
+	if first["id"] != "a-1" || first["game_id"] != float64(1) || first["confident"] != true {
+		t.Errorf("Run() first record = %v", first)
+	}
+
+	// The year matches, but the platform hint does not.
+	if second["game_id"] != float64(2) || second["confident"] != false {
+		t.Errorf("Run() second record = %v", second)
+	}
+}
+
+func TestRun_MissingTitleColumn(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	_, err := Run(context.Background(), client, FormatCSV, strings.NewReader("platform,year\nPC,2020\n"), &bytes.Buffer{}, 0)
+	if !errors.Is(err, MissingTitleColumnErr) {
+		t.Fatalf("Run() expected %v, received: %v", MissingTitleColumnErr, err)
+	}
+}
+
+func TestReader_TitlePrecedence(t *testing.T) {
+	in := `{"title":"Celeste","name":"Alice","game":"x"}
+{"title":null,"name":"Alice"}
+{"title":null}
+{"game_title":"y","Game":"x","platform":7}
+{"name":"Prey","release_year":"2006","year":2017}
+`
+
+	want := []Record{
+		{Title: "Celeste"},
+		{Title: "Alice"},
+		{Title: ""},
+		{Title: "x"},
+		{Title: "Prey", Year: 2017},
+	}
+
+	reader := newJSONLinesReader(strings.NewReader(in))
+
+	for i, w := range want {
+		record, err := reader.Read()
+		if err != nil {
+			t.Fatalf("Read() line %d error = %v", i+1, err)
+		}
+
+		if record.Title != w.Title || record.Platform != w.Platform || record.Year != w.Year {
+			t.Errorf("Read() line %d = %q, %q, %d, want %q, %q, %d", i+1, record.Title, record.Platform, record.Year, w.Title, w.Platform, w.Year)
+		}
+	}
+
+	record, err := newCSVReader(strings.NewReader("name,Title,game\nAlice,Celeste,x\n")).Read()
+	if err != nil || record.Title != "Celeste" {
+		t.Errorf("Read() CSV = %+v, %v, want title Celeste", record, err)
+	}
+}
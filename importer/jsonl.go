@@ -0,0 +1,141 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	jsonLinesReader struct {
+		s *bufio.Scanner
+	}
+
+	jsonLinesWriter struct {
+		w io.Writer
+	}
+)
+
+func newJSONLinesReader(r io.Reader) *jsonLinesReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	return &jsonLinesReader{s: s}
+}
+
+func (j *jsonLinesReader) Read() (*Record, error) {
+	for j.s.Scan() {
+		line := bytes.TrimSpace(j.s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+
+		record := &Record{}
+		if err := decoder.Decode(&record.object); err != nil {
+			return nil, err
+		}
+
+		var (
+			hasTitle    bool
+			keys        = make([]string, 0, len(record.object))
+			precedences = make(map[string]int)
+		)
+
+		for key := range record.object {
+			keys = append(keys, key)
+		}
+
+		// Keys are sorted, so the same line always reads the same keys.
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			kind, precedence := columnKind(key)
+			if kind == "" {
+				continue
+			}
+
+			if kind == "title" {
+				hasTitle = true
+			}
+
+			value, ok := jsonValue(kind, record.object[key])
+			if !ok {
+				continue
+			}
+
+			if p, ok := precedences[kind]; ok && p <= precedence {
+				continue
+			}
+			precedences[kind] = precedence
+
+			switch kind {
+			case "title":
+				record.Title = value
+			case "platform":
+				record.Platform = value
+			case "year":
+				record.Year, _ = strconv.Atoi(value)
+			}
+		}
+
+		if !hasTitle {
+			return nil, MissingTitleColumnErr
+		}
+
+		return record, nil
+	}
+
+	if err := j.s.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// jsonValue returns the value of a key read into a Record field. Titles and platforms must be strings, years may also
+// be numbers. Null and other values are treated as missing.
+func jsonValue(kind string, value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v), true
+	case json.Number:
+		return v.String(), kind == "year"
+	default:
+		return "", false
+	}
+}
+
+func newJSONLinesWriter(w io.Writer) *jsonLinesWriter {
+	return &jsonLinesWriter{w: w}
+}
+
+func (j *jsonLinesWriter) Write(record *Record, result Result) error {
+	object := make(map[string]any, len(record.object)+len(resultColumns))
+	for key, value := range record.object {
+		object[key] = value
+	}
+
+	object["game_id"] = result.GameID
+	object["game_name"] = result.GameName
+	object["similarity"] = result.Similarity
+	object["comp_main"] = result.CompMain
+	object["comp_plus"] = result.CompPlus
+	object["comp_100"] = result.Comp100
+	object["confident"] = result.Confident
+
+	line, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	_, err = j.w.Write(append(line, '\n'))
+
+	return err
+}
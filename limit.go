@@ -0,0 +1,97 @@
+package howlongtobeat
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type (
+	// ResponseTooLargeError is returned if the body of a response exceeds the maximum body size of its operation.
+	ResponseTooLargeError struct {
+		Operation Operation
+		// Limit is the maximum body size in bytes.
+		Limit int64
+	}
+
+	// limitedBody fails reads with a ResponseTooLargeError once more than the limit was read.
+	limitedBody struct {
+		io.ReadCloser
+		remaining int64
+		exceeded  bool
+		err       *ResponseTooLargeError
+	}
+)
+
+// defaultMaxBodySize is the maximum body size of a response, if not set with WithMaxBodySize.
+const defaultMaxBodySize = 16 << 20
+
+// ResponseTooLargeErr matches every ResponseTooLargeError with errors.Is.
+var ResponseTooLargeErr = errors.New("response body too large")
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("%v: %s response exceeds %d bytes", ResponseTooLargeErr, e.Operation, e.Limit)
+}
+
+func (e *ResponseTooLargeError) Is(target error) bool {
+	return target == ResponseTooLargeErr
+}
+
+// WithMaxBodySize sets the maximum body size in bytes of the responses of an operation, one of OperationToken,
+// OperationScript, OperationEndpoint, OperationSearch and OperationDetail. Larger responses fail with a
+// ResponseTooLargeError. The default is 16 MiB for every operation, a size of 0 restores the default.
+func WithMaxBodySize(op Operation, size int64) Option {
+	return func(client *Client) {
+		if client.maxBodySizes == nil {
+			client.maxBodySizes = make(map[Operation]int64)
+		}
+
+		if size <= 0 {
+			delete(client.maxBodySizes, op)
+			return
+		}
+
+		client.maxBodySizes[op] = size
+	}
+}
+
+// limitBody limits the body of the response to the maximum body size of the operation. It returns a
+// ResponseTooLargeError right away if the announced content length exceeds the limit.
+func (c *Client) limitBody(op Operation, resp *http.Response) error {
+	limit, ok := c.maxBodySizes[op]
+	if !ok {
+		limit = defaultMaxBodySize
+	}
+
+	tooLarge := &ResponseTooLargeError{Operation: op, Limit: limit}
+
+	if resp.ContentLength > limit {
+		return tooLarge
+	}
+
+	resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: limit, err: tooLarge}
+
+	return nil
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.exceeded {
+		return 0, l.err
+	}
+
+	// Read one byte more than remaining to tell a body of exactly the limit from a larger one.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.ReadCloser.Read(p)
+	if int64(n) > l.remaining {
+		l.exceeded = true
+		return int(l.remaining), l.err
+	}
+
+	l.remaining -= int64(n)
+
+	return n, err
+}
@@ -0,0 +1,88 @@
+package howlongtobeat
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func Test_WithMaxBodySize(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	page := `<html><script id="__NEXT_DATA__" type="application/json">` +
+		`{"props":{"pageProps":{"game":{"data":{"game":[{"game_id":1,"game_name":"Game"}]}},"ignWikiNav":[]}}}` +
+		`</script></html>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/game/1":
+			_, _ = io.WriteString(w, page)
+		case "/game/2":
+			// Without a content length, the limit is enforced while reading.
+			w.(http.Flusher).Flush()
+			_, _ = io.WriteString(w, strings.Repeat(" ", len(page))+page)
+		}
+	}))
+	defer server.Close()
+
+	mockClient, err := New(WithBaseURL(server.URL), WithMaxBodySize(OperationDetail, int64(len(page))))
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	// A body of exactly the limit is accepted.
+	if _, err = mockClient.Detail(ctx, 1); err != nil {
+		t.Fatalf("Detail() error = %v", err)
+	}
+
+	var tooLarge *ResponseTooLargeError
+
+	_, err = mockClient.Detail(ctx, 2)
+	if !errors.As(err, &tooLarge) || !errors.Is(err, ResponseTooLargeErr) || tooLarge.Operation != OperationDetail || tooLarge.Limit != int64(len(page)) {
+		t.Fatalf(`Detail() expected "%v" error, but received: %v`, ResponseTooLargeErr, err)
+	}
+
+	if kind := ErrorKind(err); kind != ErrorKindTooLarge {
+		t.Errorf("ErrorKind() = %q, want %q", kind, ErrorKindTooLarge)
+	}
+
+	// The announced content length is rejected before reading the body.
+	mockClient, _ = New(WithBaseURL(server.URL), WithMaxBodySize(OperationDetail, 10))
+
+	if _, err = mockClient.Detail(ctx, 1); !errors.Is(err, ResponseTooLargeErr) {
+		t.Fatalf(`Detail() expected "%v" error, but received: %v`, ResponseTooLargeErr, err)
+	}
+}
+
+func Test_readNextData(t *testing.T) {
+	document := `{"props":{"pageProps":{}}}`
+	page := strings.Repeat("<div></div>", 10000) + `<script id="__NEXT_DATA__" type="application/json">` + document + `</script>`
+
+	// The page after the document is not read.
+	reader := io.MultiReader(strings.NewReader(page), iotest.ErrReader(errors.New("read after document")))
+
+	data, err := readNextData(iotest.OneByteReader(reader))
+	if err != nil || string(data) != document {
+		t.Fatalf("readNextData() = %q, %v, want %q", data, err, document)
+	}
+
+	if data, err = readNextData(bytes.NewReader([]byte(page))); err != nil || string(data) != document {
+		t.Fatalf("readNextData() = %q, %v, want %q", data, err, document)
+	}
+
+	if _, err = readNextData(strings.NewReader(strings.TrimSuffix(page, "</script>"))); err == nil {
+		t.Errorf("readNextData() of an unterminated script returned no error")
+	}
+
+	if _, err = readNextData(strings.NewReader("<html></html>")); err == nil {
+		t.Errorf("readNextData() of a page without script returned no error")
+	}
+}
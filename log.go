@@ -0,0 +1,41 @@
+package howlongtobeat
+
+import (
+	"context"
+	"log/slog"
+)
+
+type logAttrsKey struct{}
+
+// WithLogger sets the logger for token fetches, endpoint discovery, requests, retries and parse failures.
+// Requests are logged at debug level. By default, the client does not log. The auth token is never logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(client *Client) {
+		client.logger = logger
+	}
+}
+
+// ContextWithLogAttrs returns a copy of ctx with attributes that are added to every log record of the requests made
+// with it, e.g. a request ID to correlate the logs of the client with your own.
+func ContextWithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing := logAttrsFromContext(ctx)
+
+	return context.WithValue(ctx, logAttrsKey{}, append(existing[:len(existing):len(existing)], attrs...))
+}
+
+func logAttrsFromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+
+	return attrs
+}
+
+// log writes a log record with the attributes of the context, if a logger is set.
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.logger == nil || !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	contextAttrs := logAttrsFromContext(ctx)
+
+	c.logger.LogAttrs(ctx, level, msg, append(contextAttrs[:len(contextAttrs):len(contextAttrs)], attrs...)...)
+}
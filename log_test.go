@@ -0,0 +1,88 @@
+package howlongtobeat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_WithLogger(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 1, GameName: "Game"}})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockClient := server.client(t, WithLogger(logger))
+
+	ctx = ContextWithLogAttrs(ctx, slog.String("request_id", "abc"))
+
+	if _, err := mockClient.Search(ctx, "Game", SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if _, err := mockClient.Detail(ctx, 2); err == nil {
+		t.Fatalf("Detail() expected error for unknown game")
+	}
+
+	if strings.Contains(buf.String(), "mock-token") {
+		t.Fatalf("log contains the auth token:\n%s", buf.String())
+	}
+
+	var (
+		messages []string
+		statuses []float64
+	)
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log record %q: %v", line, err)
+		}
+
+		if record["request_id"] != "abc" {
+			t.Errorf("log record without request_id: %s", line)
+		}
+
+		messages = append(messages, record["msg"].(string))
+		if status, ok := record["status"].(float64); ok && record["msg"] == "request completed" {
+			statuses = append(statuses, status)
+		}
+	}
+
+	want := []string{"request completed", "fetched auth token", "request completed", "request completed"}
+
+	if strings.Join(messages, ",") != strings.Join(want, ",") {
+		t.Errorf("log messages = %q, want %q", messages, want)
+	}
+
+	if len(statuses) != 3 || statuses[1] != http.StatusOK || statuses[2] != http.StatusNotFound {
+		t.Errorf("logged statuses = %v", statuses)
+	}
+}
+
+func Test_ContextWithLogAttrs(t *testing.T) {
+	parent := ContextWithLogAttrs(context.Background(), slog.String("a", "1"))
+	first := ContextWithLogAttrs(parent, slog.String("b", "2"))
+	second := ContextWithLogAttrs(parent, slog.String("c", "3"))
+
+	if got := logAttrsFromContext(first); len(got) != 2 || got[1].Key != "b" {
+		t.Errorf("logAttrsFromContext() = %v", got)
+	}
+
+	if got := logAttrsFromContext(second); len(got) != 2 || got[1].Key != "c" {
+		t.Errorf("logAttrsFromContext() = %v", got)
+	}
+}
+
+func Test_log_WithoutLogger(t *testing.T) {
+	// A client without a logger must not panic.
+	(&Client{}).log(context.Background(), slog.LevelError, "message")
+}
@@ -0,0 +1,77 @@
+package howlongtobeat
+
+import (
+	"context"
+	"net/http"
+)
+
+type (
+	// Operation is the logical operation a request to HowLongToBeat belongs to.
+	Operation string
+
+	// Middleware hooks into every request the Client sends. All hooks are optional.
+	Middleware struct {
+		// BeforeRequest is called before the request is sent and may modify it, e.g. to add tracing headers or to
+		// replace the User-Agent. Returning an error aborts the request.
+		BeforeRequest func(op Operation, req *http.Request) error
+		// AfterResponse is called with every response before its body is parsed. It must not consume the body.
+		AfterResponse func(op Operation, req *http.Request, resp *http.Response)
+		// OnError is called if the request fails, including unexpected status codes and parse failures.
+		OnError func(op Operation, req *http.Request, err error)
+	}
+
+	operationKey struct{}
+)
+
+const (
+	OperationToken    Operation = "token"
+	OperationScript   Operation = "script"
+	OperationEndpoint Operation = "endpoint"
+	OperationSearch   Operation = "search"
+	OperationDetail   Operation = "detail"
+)
+
+// WithMiddleware adds middleware to the Client. The hooks of multiple middleware are called in the order they were
+// added, across multiple WithMiddleware options as well.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(client *Client) {
+		client.middleware = append(client.middleware, middleware...)
+	}
+}
+
+// OperationFromContext returns the operation of a request from its context, e.g. inside a custom http.RoundTripper.
+func OperationFromContext(ctx context.Context) (Operation, bool) {
+	op, ok := ctx.Value(operationKey{}).(Operation)
+
+	return op, ok
+}
+
+func (c *Client) beforeRequest(op Operation, req *http.Request) error {
+	for _, m := range c.middleware {
+		if m.BeforeRequest == nil {
+			continue
+		}
+
+		if err := m.BeforeRequest(op, req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) afterResponse(op Operation, req *http.Request, resp *http.Response) {
+	for _, m := range c.middleware {
+		if m.AfterResponse != nil {
+			m.AfterResponse(op, req, resp)
+		}
+	}
+}
+
+func (c *Client) onError(op Operation, req *http.Request, err error) {
+	for _, m := range c.middleware {
+		if m.OnError != nil {
+			m.OnError(op, req, err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_WithMiddleware(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 1, GameName: "Game"}})
+
+	var userAgents []string
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		server.serveSearch(w, r)
+	}
+
+	var (
+		before   []Operation
+		statuses []int
+		failed   []Operation
+	)
+
+	mockClient := server.client(t, WithMiddleware(Middleware{
+		BeforeRequest: func(op Operation, req *http.Request) error {
+			if ctxOp, ok := OperationFromContext(req.Context()); !ok || ctxOp != op {
+				t.Errorf("OperationFromContext() = %q, want %q", ctxOp, op)
+			}
+			before = append(before, op)
+			req.Header.Set("User-Agent", "hltb-test")
+			return nil
+		},
+		AfterResponse: func(op Operation, req *http.Request, resp *http.Response) {
+			statuses = append(statuses, resp.StatusCode)
+		},
+		OnError: func(op Operation, req *http.Request, err error) {
+			failed = append(failed, op)
+		},
+	}))
+
+	if _, err := mockClient.Search(ctx, "Game", SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if _, err := mockClient.Detail(ctx, 2); err == nil {
+		t.Fatalf("Detail() expected error for unknown game")
+	}
+
+	if want := []Operation{OperationToken, OperationSearch, OperationDetail}; !reflect.DeepEqual(before, want) {
+		t.Errorf("BeforeRequest() operations = %v, want %v", before, want)
+	}
+
+	if want := []int{http.StatusOK, http.StatusOK, http.StatusNotFound}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("AfterResponse() statuses = %v, want %v", statuses, want)
+	}
+
+	if want := []Operation{OperationDetail}; !reflect.DeepEqual(failed, want) {
+		t.Errorf("OnError() operations = %v, want %v", failed, want)
+	}
+
+	if len(userAgents) != 1 || userAgents[0] != "hltb-test" {
+		t.Errorf("User-Agent = %v, want hltb-test", userAgents)
+	}
+}
+
+func Test_WithMiddleware_AbortRequest(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 1, GameName: "Game"}})
+
+	abortErr := errors.New("aborted")
+
+	var onError error
+	mockClient := server.client(t, WithMiddleware(
+		Middleware{BeforeRequest: func(op Operation, req *http.Request) error { return abortErr }},
+		Middleware{OnError: func(op Operation, req *http.Request, err error) { onError = err }},
+	))
+
+	if _, err := mockClient.Detail(ctx, 1); !errors.Is(err, abortErr) {
+		t.Fatalf(`Detail() expected "%v" error, but received: %v`, abortErr, err)
+	}
+
+	if !errors.Is(onError, abortErr) {
+		t.Errorf("OnError() error = %v, want %v", onError, abortErr)
+	}
+
+	if calls := server.detailCalls.Load(); calls != 0 {
+		t.Errorf("Detail() sent %d requests, want 0", calls)
+	}
+}
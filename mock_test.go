@@ -0,0 +1,202 @@
+package howlongtobeat
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// mockGame is a single game served by the mock HLTB server.
+type mockGame struct {
+	game          GameDetailsGameDataGame
+	relationships []GameDetailsGameDataRelationships
+}
+
+// mockServer is a minimal in-process HowLongToBeat used by the tests in this package.
+type mockServer struct {
+	*httptest.Server
+	games         map[int]mockGame
+	detailCalls   atomic.Int32
+	searchCalls   atomic.Int32
+	tokenCalls    atomic.Int32
+	searchHandler http.HandlerFunc
+}
+
+// rewriteTransport redirects every outgoing request to the mock server.
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (r *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+	req.Host = r.target.Host
+
+	return r.base.RoundTrip(req)
+}
+
+func newMockServer(t *testing.T, games ...mockGame) *mockServer {
+	t.Helper()
+
+	m := &mockServer{games: make(map[int]mockGame, len(games))}
+	for _, g := range games {
+		m.games[g.game.GameID] = g
+	}
+
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+// client returns a Client whose requests are all routed to the mock server.
+func (m *mockServer) client(t *testing.T, options ...Option) *Client {
+	t.Helper()
+
+	target, err := url.Parse(m.URL)
+	if err != nil {
+		t.Fatalf("parse mock server url: %v", err)
+	}
+
+	httpClient := &http.Client{Transport: &rewriteTransport{target: target, base: http.DefaultTransport}}
+
+	c, err := New(append([]Option{WithHTTPClient(httpClient)}, options...)...)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	return c
+}
+
+func (m *mockServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := "/" + strings.TrimLeft(r.URL.Path, "/")
+
+	switch {
+	case path == "/api/finder/init":
+		m.tokenCalls.Add(1)
+		_ = json.NewEncoder(w).Encode(TokenResponse{Token: "mock-token"})
+	case path == hltbSearchEndpoint && r.Method == http.MethodPost:
+		m.searchCalls.Add(1)
+		if m.searchHandler != nil {
+			m.searchHandler(w, r)
+			return
+		}
+		m.serveSearch(w, r)
+	case strings.HasPrefix(path, "/game/"):
+		m.detailCalls.Add(1)
+		m.serveDetail(w, strings.TrimPrefix(path, "/game/"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *mockServer) serveSearch(w http.ResponseWriter, r *http.Request) {
+	var req searchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp := SearchGame{Color: "blue", Category: "games", PageCurrent: 1, PageTotal: 1, PageSize: req.Size}
+
+	for _, id := range m.sortedIDs() {
+		g := m.games[id].game
+		if !matchesTerms(g.GameName, req.SearchTerms) {
+			continue
+		}
+
+		year, _ := strconv.Atoi(strings.SplitN(g.ReleaseWorld, "-", 2)[0])
+
+		resp.Data = append(resp.Data, SearchGameData{
+			GameID:          g.GameID,
+			GameName:        g.GameName,
+			GameType:        g.GameType,
+			CompMain:        g.CompMain,
+			CompPlus:        g.CompPlus,
+			Comp100:         g.Comp100,
+			CompAll:         g.CompAll,
+			ProfileSteam:    g.ProfileSteam,
+			ProfilePlatform: g.ProfilePlatform,
+			ReleaseWorld:    year,
+		})
+	}
+
+	resp.Count = len(resp.Data)
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (m *mockServer) serveDetail(w http.ResponseWriter, rawID string) {
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	g, ok := m.games[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var resp gameDetailsResponse
+	resp.Props.PageProps.Game.Count = 1
+	resp.Props.PageProps.Game.Data.Game = []GameDetailsGameDataGame{g.game}
+	resp.Props.PageProps.Game.Data.Relationships = g.relationships
+	resp.Props.PageProps.IgnWikiNav = json.RawMessage("[]")
+	resp.Page = "/game/[gameId]"
+	resp.Query.GameID = rawID
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, `<html><body><script id="__NEXT_DATA__" type="application/json">%s</script></body></html>`, data)
+}
+
+func (m *mockServer) sortedIDs() []int {
+	ids := make([]int, 0, len(m.games))
+	for id := range m.games {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+func matchesTerms(name string, terms []string) bool {
+	name = strings.ToLower(name)
+
+	for _, term := range terms {
+		if !strings.Contains(name, strings.ToLower(term)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// relationship converts a mock game into the relationship entry HLTB lists on related games.
+func (g mockGame) relationship() GameDetailsGameDataRelationships {
+	return GameDetailsGameDataRelationships{
+		GameID:   g.game.GameID,
+		GameName: g.game.GameName,
+		GameType: g.game.GameType,
+		CompMain: g.game.CompMain,
+		CompPlus: g.game.CompPlus,
+		Comp100:  g.game.Comp100,
+		CompAll:  g.game.CompAll,
+	}
+}
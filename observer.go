@@ -0,0 +1,155 @@
+package howlongtobeat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+type (
+	// Observer is notified about the start and the end of every operation of the Client, e.g. to record traces or
+	// metrics. Observed operations are OperationSearch, OperationDetail, OperationToken and OperationDiscovery.
+	Observer interface {
+		// OperationStart is called when an operation starts. The returned context is used for the operation and passed
+		// to OperationEnd, e.g. to carry a span. Operations started during the operation, like a token fetch during a
+		// search, receive the returned context as their parent.
+		OperationStart(ctx context.Context, op Operation) context.Context
+		// OperationEnd is called when an operation has finished.
+		OperationEnd(ctx context.Context, op Operation, info OperationInfo)
+	}
+
+	// OperationInfo describes a finished operation. Fields that do not apply to an operation are left empty.
+	OperationInfo struct {
+		// GameID is the game of a detail operation.
+		GameID int
+		// Page is the requested result page of a search operation.
+		Page int
+		// ResultCount is the number of results of a search operation.
+		ResultCount int
+		// StatusCode is the status code of the response, 0 if no response was received or the status code was not
+		// the cause of the error.
+		StatusCode int
+		// CacheHit is set if the result was served from the cache without a request.
+		CacheHit bool
+		// Retries is the number of times the operation was retried.
+		Retries int
+		// Refresh is set for token fetches that replace a token rejected by HowLongToBeat.
+		Refresh bool
+		// Scripts are the scripts scanned by an endpoint discovery.
+		Scripts []ScriptScan
+		// Duration is the duration of the whole operation.
+		Duration time.Duration
+		// Err is the error the operation failed with.
+		Err error
+	}
+
+	// observation is a started operation.
+	observation struct {
+		client *Client
+		ctx    context.Context
+		op     Operation
+		start  time.Time
+	}
+)
+
+// OperationDiscovery is the discovery of the search endpoint from the scripts of the homepage.
+const OperationDiscovery Operation = "discovery"
+
+// Error kinds returned by ErrorKind.
+const (
+	ErrorKindCanceled    = "canceled"
+	ErrorKindCircuitOpen = "circuit_open"
+	ErrorKindTimeout     = "timeout"
+	ErrorKindStatus      = "status"
+	ErrorKindNetwork     = "network"
+	ErrorKindParse       = "parse"
+	ErrorKindTooLarge    = "too_large"
+	ErrorKindOther       = "other"
+)
+
+// WithObserver adds an observer to the Client. Multiple observers are notified in the order they were added.
+func WithObserver(observer Observer) Option {
+	return func(client *Client) {
+		client.observers = append(client.observers, observer)
+	}
+}
+
+// ErrorKind classifies the error of an operation for metrics, e.g. "timeout" or "status".
+// It returns an empty string for a nil error.
+func ErrorKind(err error) string {
+	var (
+		statusErr    *StatusError
+		netErr       net.Error
+		syntaxErr    *json.SyntaxError
+		unmarshalErr *json.UnmarshalTypeError
+	)
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled
+	case errors.Is(err, CircuitOpenErr):
+		return ErrorKindCircuitOpen
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorKindTimeout
+	case errors.As(err, &statusErr):
+		return ErrorKindStatus
+	case errors.As(err, &netErr):
+		return ErrorKindNetwork
+	case errors.Is(err, ResponseTooLargeErr):
+		return ErrorKindTooLarge
+	case errors.As(err, &syntaxErr), errors.As(err, &unmarshalErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorKindParse
+	default:
+		return ErrorKindOther
+	}
+}
+
+// statusCodeOf returns the status code of a StatusError in the chain of err, or 0.
+func statusCodeOf(err error) int {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+
+	return 0
+}
+
+// observe starts an operation. It returns the context to use for the operation and an observation that must be ended.
+func (c *Client) observe(ctx context.Context, op Operation) (context.Context, *observation) {
+	if len(c.observers) == 0 {
+		return ctx, nil
+	}
+
+	for _, observer := range c.observers {
+		ctx = observer.OperationStart(ctx, op)
+	}
+
+	return ctx, &observation{client: c, ctx: ctx, op: op, start: time.Now()}
+}
+
+// end notifies the observers that the operation has finished. It is a no-op on a nil observation.
+func (o *observation) end(info OperationInfo) {
+	if o == nil {
+		return
+	}
+
+	info.Duration = time.Since(o.start)
+
+	// Requests only succeed with 200, so the status code is known unless the result came from the cache.
+	switch {
+	case info.Err != nil:
+		info.StatusCode = statusCodeOf(info.Err)
+	case !info.CacheHit:
+		info.StatusCode = http.StatusOK
+	}
+
+	for i := len(o.client.observers) - 1; i >= 0; i-- {
+		o.client.observers[i].OperationEnd(o.ctx, o.op, info)
+	}
+}
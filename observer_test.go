@@ -0,0 +1,131 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	observerKey struct{}
+
+	// recordingObserver records the operations it observes.
+	recordingObserver struct {
+		mu      sync.Mutex
+		started []Operation
+		ended   []observedOperation
+	}
+
+	observedOperation struct {
+		op     Operation
+		parent Operation
+		info   OperationInfo
+	}
+)
+
+func (o *recordingObserver) OperationStart(ctx context.Context, op Operation) context.Context {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.started = append(o.started, op)
+
+	return context.WithValue(ctx, observerKey{}, append(o.stack(ctx), op))
+}
+
+func (o *recordingObserver) OperationEnd(ctx context.Context, op Operation, info OperationInfo) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var parent Operation
+	if stack := o.stack(ctx); len(stack) > 1 {
+		parent = stack[len(stack)-2]
+	}
+
+	o.ended = append(o.ended, observedOperation{op: op, parent: parent, info: info})
+}
+
+func (o *recordingObserver) stack(ctx context.Context) []Operation {
+	stack, _ := ctx.Value(observerKey{}).([]Operation)
+
+	return stack[:len(stack):len(stack)]
+}
+
+func Test_WithObserver(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 1, GameName: "Game"}})
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		if server.searchCalls.Load() == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		server.serveSearch(w, r)
+	}
+
+	observer := &recordingObserver{}
+	mockClient := server.client(t, WithObserver(observer), WithCache(time.Minute, 10), WithSearchRetry())
+
+	options := &SearchOptions{Pagination: &SearchGamePagination{Page: 2}}
+
+	for i := 0; i < 2; i++ {
+		if _, err := mockClient.Search(ctx, "Game", SearchModifierNone, options); err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+	}
+
+	if _, err := mockClient.Detail(ctx, 2); err == nil {
+		t.Fatalf("Detail() expected error for unknown game")
+	}
+
+	want := []Operation{OperationToken, OperationToken, OperationSearch, OperationSearch, OperationDetail}
+	if len(observer.ended) != len(want) {
+		t.Fatalf("OperationEnd() called %d times, want %d: %+v", len(observer.ended), len(want), observer.ended)
+	}
+
+	for i, op := range want {
+		if observer.ended[i].op != op {
+			t.Errorf("OperationEnd() #%d = %q, want %q", i, observer.ended[i].op, op)
+		}
+	}
+
+	token, refresh, search, cached, detail := observer.ended[0], observer.ended[1], observer.ended[2], observer.ended[3], observer.ended[4]
+
+	if token.parent != OperationSearch || token.info.Refresh || !refresh.info.Refresh {
+		t.Errorf("token operations = %+v, %+v", token, refresh)
+	}
+
+	if search.info.Page != 2 || search.info.ResultCount != 1 || search.info.Retries != 1 || search.info.StatusCode != http.StatusOK || search.info.CacheHit {
+		t.Errorf("search operation = %+v", search.info)
+	}
+
+	if !cached.info.CacheHit || cached.info.StatusCode != 0 {
+		t.Errorf("cached search operation = %+v", cached.info)
+	}
+
+	if detail.info.GameID != 2 || detail.info.StatusCode != http.StatusNotFound || detail.info.Err == nil {
+		t.Errorf("detail operation = %+v", detail.info)
+	}
+}
+
+func Test_ErrorKind(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{context.Canceled, ErrorKindCanceled},
+		{context.DeadlineExceeded, ErrorKindTimeout},
+		{&StatusError{StatusCode: http.StatusTooManyRequests}, ErrorKindStatus},
+		{errors.New("boom"), ErrorKindOther},
+	}
+
+	for _, tt := range tests {
+		if got := ErrorKind(tt.err); got != tt.want {
+			t.Errorf("ErrorKind(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
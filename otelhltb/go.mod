@@ -0,0 +1,18 @@
+module github.com/forbiddencoding/howlongtobeat/otelhltb
+
+go 1.21
+
+require (
+	github.com/forbiddencoding/howlongtobeat v0.0.0-20261018230337-0a558b8d7e0b
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/metric v1.24.0
+	go.opentelemetry.io/otel/sdk v1.24.0
+	go.opentelemetry.io/otel/sdk/metric v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.1 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+)
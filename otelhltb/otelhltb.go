@@ -0,0 +1,191 @@
+// Package otelhltb instruments a howlongtobeat.Client with OpenTelemetry traces and metrics.
+//
+// Spans are created for searches, game details, token fetches and endpoint discoveries. Token fetches and discoveries
+// are children of the search that triggered them. The following metrics are recorded:
+//
+//	hltb.client.operation.duration  histogram of the operation duration in seconds
+//	hltb.client.errors              counter of failed operations by error kind
+//	hltb.client.retries             counter of retried operations
+//	hltb.client.token.refreshes     counter of tokens fetched to replace a rejected token
+//
+// All metrics carry the hltb.operation attribute.
+package otelhltb
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+type (
+	// Option configures the instrumentation.
+	Option func(cfg *config)
+
+	config struct {
+		tracerProvider trace.TracerProvider
+		meterProvider  metric.MeterProvider
+	}
+
+	// Observer is a howlongtobeat.Observer recording OpenTelemetry spans and metrics.
+	Observer struct {
+		tracer         trace.Tracer
+		duration       metric.Float64Histogram
+		errors         metric.Int64Counter
+		retries        metric.Int64Counter
+		tokenRefreshes metric.Int64Counter
+	}
+)
+
+// ScopeName is the instrumentation scope of the tracer and the meter.
+const ScopeName = "github.com/forbiddencoding/howlongtobeat/otelhltb"
+
+// Attribute keys of spans and metrics.
+const (
+	OperationKey  = attribute.Key("hltb.operation")
+	GameIDKey     = attribute.Key("hltb.game_id")
+	PageKey       = attribute.Key("hltb.page")
+	ResultsKey    = attribute.Key("hltb.result_count")
+	CacheHitKey   = attribute.Key("hltb.cache_hit")
+	RetriesKey    = attribute.Key("hltb.retries")
+	RefreshKey    = attribute.Key("hltb.token_refresh")
+	StatusCodeKey = attribute.Key("http.response.status_code")
+	ErrorTypeKey  = attribute.Key("error.type")
+)
+
+// WithTracerProvider sets the tracer provider. The default is the global tracer provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(cfg *config) {
+		cfg.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider. The default is the global meter provider.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(cfg *config) {
+		cfg.meterProvider = provider
+	}
+}
+
+// Instrument returns a howlongtobeat.Option that instruments the client.
+// Errors creating the metric instruments are passed to the global OpenTelemetry error handler.
+func Instrument(options ...Option) howlongtobeat.Option {
+	observer, err := NewObserver(options...)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return howlongtobeat.WithObserver(observer)
+}
+
+// NewObserver creates a new Observer. Use it with howlongtobeat.WithObserver, or use Instrument instead.
+// If an instrument cannot be created, the error is returned together with an Observer using no-op instruments for it.
+func NewObserver(options ...Option) (*Observer, error) {
+	cfg := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+
+	o := &Observer{tracer: cfg.tracerProvider.Tracer(ScopeName)}
+
+	var err, instrumentErr error
+
+	o.duration, instrumentErr = meter.Float64Histogram("hltb.client.operation.duration",
+		metric.WithDescription("Duration of HowLongToBeat client operations."),
+		metric.WithUnit("s"),
+	)
+	err = errors.Join(err, instrumentErr)
+
+	o.errors, instrumentErr = meter.Int64Counter("hltb.client.errors",
+		metric.WithDescription("Number of failed HowLongToBeat client operations."),
+		metric.WithUnit("{error}"),
+	)
+	err = errors.Join(err, instrumentErr)
+
+	o.retries, instrumentErr = meter.Int64Counter("hltb.client.retries",
+		metric.WithDescription("Number of retries of HowLongToBeat client operations."),
+		metric.WithUnit("{retry}"),
+	)
+	err = errors.Join(err, instrumentErr)
+
+	o.tokenRefreshes, instrumentErr = meter.Int64Counter("hltb.client.token.refreshes",
+		metric.WithDescription("Number of auth tokens fetched to replace a rejected token."),
+		metric.WithUnit("{token}"),
+	)
+	err = errors.Join(err, instrumentErr)
+
+	return o, err
+}
+
+// OperationStart starts a span for the operation.
+func (o *Observer) OperationStart(ctx context.Context, op howlongtobeat.Operation) context.Context {
+	ctx, _ = o.tracer.Start(ctx, "hltb."+string(op),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(OperationKey.String(string(op))),
+	)
+
+	return ctx
+}
+
+// OperationEnd ends the span of the operation and records its metrics.
+func (o *Observer) OperationEnd(ctx context.Context, op howlongtobeat.Operation, info howlongtobeat.OperationInfo) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(spanAttributes(op, info)...)
+
+	operation := OperationKey.String(string(op))
+	metricAttrs := []attribute.KeyValue{operation, CacheHitKey.Bool(info.CacheHit)}
+
+	if info.Err != nil {
+		errorType := ErrorTypeKey.String(howlongtobeat.ErrorKind(info.Err))
+
+		span.RecordError(info.Err)
+		span.SetStatus(codes.Error, info.Err.Error())
+		span.SetAttributes(errorType)
+
+		metricAttrs = append(metricAttrs, errorType)
+		o.errors.Add(ctx, 1, metric.WithAttributes(operation, errorType))
+	}
+
+	o.duration.Record(ctx, info.Duration.Seconds(), metric.WithAttributes(metricAttrs...))
+
+	if info.Retries > 0 {
+		o.retries.Add(ctx, int64(info.Retries), metric.WithAttributes(operation))
+	}
+
+	if info.Refresh {
+		o.tokenRefreshes.Add(ctx, 1, metric.WithAttributes(operation))
+	}
+
+	span.End()
+}
+
+func spanAttributes(op howlongtobeat.Operation, info howlongtobeat.OperationInfo) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+
+	switch op {
+	case howlongtobeat.OperationSearch:
+		attrs = append(attrs, PageKey.Int(info.Page), ResultsKey.Int(info.ResultCount), RetriesKey.Int(info.Retries), CacheHitKey.Bool(info.CacheHit))
+	case howlongtobeat.OperationDetail:
+		attrs = append(attrs, GameIDKey.Int(info.GameID), CacheHitKey.Bool(info.CacheHit))
+	case howlongtobeat.OperationToken:
+		attrs = append(attrs, RefreshKey.Bool(info.Refresh))
+	}
+
+	if info.StatusCode != 0 {
+		attrs = append(attrs, StatusCodeKey.Int(info.StatusCode))
+	}
+
+	return attrs
+}
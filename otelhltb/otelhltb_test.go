@@ -0,0 +1,166 @@
+package otelhltb_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/forbiddencoding/howlongtobeat"
+	"github.com/forbiddencoding/howlongtobeat/hltbtest"
+	"github.com/forbiddencoding/howlongtobeat/otelhltb"
+)
+
+func newInstrumentedClient(t *testing.T) (*hltbtest.Server, *howlongtobeat.Client, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	server := hltbtest.NewServer()
+	t.Cleanup(server.Close)
+	server.AddGame(howlongtobeat.GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt"})
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	client, err := server.NewClient(otelhltb.Instrument(
+		otelhltb.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		otelhltb.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	), howlongtobeat.WithSearchRetry())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	return server, client, spans, reader
+}
+
+func TestInstrument_Spans(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server, client, spans, _ := newInstrumentedClient(t)
+
+	if _, err := client.Search(ctx, "Witcher", howlongtobeat.SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	server.InjectFault(hltbtest.EndpointDetail, hltbtest.Fault{StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+	if _, err := client.Detail(ctx, 10270); err == nil {
+		t.Fatalf("Detail() expected error")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("recorded %d spans, want 3", len(ended))
+	}
+
+	token, search, detail := ended[0], ended[1], ended[2]
+
+	if token.Name() != "hltb.token" || search.Name() != "hltb.search" || detail.Name() != "hltb.detail" {
+		t.Fatalf("span names = %q, %q, %q", token.Name(), search.Name(), detail.Name())
+	}
+
+	if token.Parent().SpanID() != search.SpanContext().SpanID() {
+		t.Errorf("token span is not a child of the search span")
+	}
+
+	assertAttribute(t, search.Attributes(), otelhltb.ResultsKey, attribute.IntValue(1))
+	assertAttribute(t, search.Attributes(), otelhltb.PageKey, attribute.IntValue(1))
+	assertAttribute(t, search.Attributes(), otelhltb.StatusCodeKey, attribute.IntValue(http.StatusOK))
+	assertAttribute(t, detail.Attributes(), otelhltb.GameIDKey, attribute.IntValue(10270))
+	assertAttribute(t, detail.Attributes(), otelhltb.StatusCodeKey, attribute.IntValue(http.StatusServiceUnavailable))
+	assertAttribute(t, detail.Attributes(), otelhltb.ErrorTypeKey, attribute.StringValue(howlongtobeat.ErrorKindStatus))
+
+	if detail.Status().Code != codes.Error {
+		t.Errorf("detail span status = %v, want %v", detail.Status().Code, codes.Error)
+	}
+}
+
+func TestInstrument_Metrics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server, client, _, reader := newInstrumentedClient(t)
+
+	if _, err := client.Search(ctx, "Witcher", howlongtobeat.SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	server.RotateToken("rotated")
+
+	if _, err := client.Search(ctx, "Witcher 3", howlongtobeat.SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &data); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	if got := counterValue(t, metrics["hltb.client.retries"]); got != 1 {
+		t.Errorf("hltb.client.retries = %d, want 1", got)
+	}
+
+	if got := counterValue(t, metrics["hltb.client.token.refreshes"]); got != 1 {
+		t.Errorf("hltb.client.token.refreshes = %d, want 1", got)
+	}
+
+	histogram, ok := metrics["hltb.client.operation.duration"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("hltb.client.operation.duration = %T, want histogram", metrics["hltb.client.operation.duration"])
+	}
+
+	var count uint64
+	for _, point := range histogram.DataPoints {
+		count += point.Count
+	}
+
+	// Two searches and two token fetches.
+	if count != 4 {
+		t.Errorf("hltb.client.operation.duration count = %d, want 4", count)
+	}
+}
+
+func assertAttribute(t *testing.T, attrs []attribute.KeyValue, key attribute.Key, want attribute.Value) {
+	t.Helper()
+
+	for _, attr := range attrs {
+		if attr.Key == key {
+			if attr.Value != want {
+				t.Errorf("attribute %s = %v, want %v", key, attr.Value.Emit(), want.Emit())
+			}
+			return
+		}
+	}
+
+	t.Errorf("attribute %s not found", key)
+}
+
+func counterValue(t *testing.T, data metricdata.Aggregation) int64 {
+	t.Helper()
+
+	sum, ok := data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("counter = %T, want sum", data)
+	}
+
+	var value int64
+	for _, point := range sum.DataPoints {
+		value += point.Value
+	}
+
+	return value
+}
@@ -15,24 +15,111 @@ type parseResponseFunc func(resp *http.Response) error
 // jsonParser returns a function that will decode the body of an http.Response as JSON into the provided struct.
 func (c *Client) jsonParser(val any) parseResponseFunc {
 	return func(resp *http.Response) error {
-		return json.NewDecoder(resp.Body).Decode(val)
+		if c.driftHook == nil && !c.rawPayloads {
+			return json.NewDecoder(resp.Body).Decode(val)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		if err = json.Unmarshal(body, val); err != nil {
+			return err
+		}
+
+		c.retainRawPayload(body, val)
+		c.reportDrift(resp, body, val)
+
+		return nil
 	}
 }
 
+// nextDataParser returns a function that will decode the __NEXT_DATA__ document of an HTML page into the provided struct.
 func (c *Client) nextDataParser(val any) parseResponseFunc {
 	return func(resp *http.Response) error {
-		startTag := []byte(`<script id="__NEXT_DATA__" type="application/json">`)
-		endTag := []byte(`</script>`)
-
-		body, err := io.ReadAll(resp.Body)
+		data, err := readNextData(resp.Body)
 		if err != nil {
 			return err
 		}
 
-		start := bytes.Index(body, startTag)
-		end := bytes.Index(body[start:], endTag)
+		if err = json.Unmarshal(data, &val); err != nil {
+			return err
+		}
+
+		c.retainRawPayload(data, val)
+		c.reportDrift(resp, data, val)
 
-		return json.Unmarshal(body[start+len(startTag):start+end], &val)
+		return nil
+	}
+}
+
+// retainRawPayload stores the payload on val, if raw payloads are enabled.
+func (c *Client) retainRawPayload(payload []byte, val any) {
+	if setter, ok := val.(rawPayloadSetter); ok && c.rawPayloads {
+		setter.setRawPayload(json.RawMessage(payload))
+	}
+}
+
+var (
+	nextDataStartTag = []byte(`<script id="__NEXT_DATA__" type="application/json">`)
+	nextDataEndTag   = []byte(`</script>`)
+)
+
+// extractNextData returns the JSON document of the __NEXT_DATA__ script of an HTML page.
+func extractNextData(body []byte) ([]byte, error) {
+	return readNextData(bytes.NewReader(body))
+}
+
+// readNextData reads the JSON document of the __NEXT_DATA__ script of an HTML page. Only the document itself is
+// buffered, the page before it is discarded while reading and the page after it is not read.
+func readNextData(r io.Reader) ([]byte, error) {
+	var (
+		chunk = make([]byte, 32<<10)
+		// window holds the end of the page read so far, which may contain the beginning of the start tag.
+		window []byte
+		data   []byte
+		found  bool
+		// scanned is the length of data already searched for the end tag.
+		scanned int
+	)
+
+	for {
+		n, err := r.Read(chunk)
+
+		if found {
+			data = append(data, chunk[:n]...)
+		} else {
+			window = append(window, chunk[:n]...)
+
+			if start := bytes.Index(window, nextDataStartTag); start != -1 {
+				found = true
+				data = append([]byte(nil), window[start+len(nextDataStartTag):]...)
+				window = nil
+			} else if keep := len(nextDataStartTag) - 1; len(window) > keep {
+				window = append(window[:0], window[len(window)-keep:]...)
+			}
+		}
+
+		if found {
+			if end := bytes.Index(data[scanned:], nextDataEndTag); end != -1 {
+				return data[:scanned+end], nil
+			}
+
+			scanned = max(0, len(data)-len(nextDataEndTag)+1)
+		}
+
+		if errors.Is(err, io.EOF) {
+			if found {
+				return nil, errors.New("__NEXT_DATA__ script not terminated")
+			}
+
+			return nil, errors.New("__NEXT_DATA__ script not found")
+		}
+
+		if err != nil {
+			return nil, err
+		}
 	}
 }
 
@@ -84,9 +171,10 @@ func (c *Client) endpointParser(apiData *ApiData) parseResponseFunc {
 		}
 
 		reg := regexp.MustCompile(`(?si)fetch\s*\(\s*["']/api/([a-zA-Z0-9_/]+)[^"']*["']\s*,\s*{[^}]*method:\s*["']POST["'][^}]*}`)
+		// Most scripts do not contain the endpoint, the endpoint path is left empty then.
 		matches := reg.FindSubmatch(body)
 		if len(matches) < 2 {
-			return errors.New("endpoint path not found")
+			return nil
 		}
 
 		var basePath string
@@ -157,3 +157,23 @@ func Test_endpointParser(t *testing.T) {
 		t.Fatalf("unexpected endpoint path: %s, expected: %s", apiData.endpointPath, expectedPath)
 	}
 }
+
+func Test_nextDataParser_MissingScript(t *testing.T) {
+	rs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`<html><body>Access denied</body></html>`))
+	}))
+	defer rs.Close()
+
+	resp, err := http.Get(rs.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mockClient := &Client{}
+	parseFunc := mockClient.nextDataParser(&gameDetailsResponse{})
+
+	if err = parseFunc(resp); err == nil {
+		t.Fatalf("expected error for missing __NEXT_DATA__ script")
+	}
+}
@@ -0,0 +1,106 @@
+package howlongtobeat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rawPayloadSetter is implemented by the responses that can retain their raw payload.
+type rawPayloadSetter interface {
+	setRawPayload(raw json.RawMessage)
+}
+
+var (
+	RawPayloadUnavailableErr = errors.New("raw payload unavailable, enable it with WithRawPayloads")
+	PathNotFoundErr          = errors.New("path not found")
+)
+
+// WithRawPayloads retains the raw upstream payloads on the results: the search response on SearchGame.Raw and the
+// __NEXT_DATA__ document on GameDetails.Raw. Use Lookup to read fields that are not modelled by the structs yet.
+func WithRawPayloads() Option {
+	return func(client *Client) {
+		client.rawPayloads = true
+	}
+}
+
+// Lookup decodes the value at path of the raw search response into v.
+// The path uses dot separated keys and indices, e.g. "data.0.game_name" or "data[0].game_name".
+// The raw response is in upstream order, not sorted by similarity like Data.
+func (s *SearchGame) Lookup(path string, v any) error {
+	return LookupRaw(s.Raw, path, v)
+}
+
+// Lookup decodes the value at path of the raw __NEXT_DATA__ document into v,
+// e.g. "props.pageProps.game.data.game[0].count_discussion".
+func (g *GameDetails) Lookup(path string, v any) error {
+	return LookupRaw(g.Raw, path, v)
+}
+
+// LookupRaw decodes the value at path of a raw JSON document into v. See SearchGame.Lookup for the path syntax.
+// An empty path decodes the whole document.
+func LookupRaw(raw json.RawMessage, path string, v any) error {
+	if len(raw) == 0 {
+		return RawPayloadUnavailableErr
+	}
+
+	current := raw
+
+	for _, segment := range splitPath(path) {
+		next, err := lookupSegment(current, segment)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, path)
+		}
+		current = next
+	}
+
+	return json.Unmarshal(current, v)
+}
+
+func lookupSegment(raw json.RawMessage, segment string) (json.RawMessage, error) {
+	if index, err := strconv.Atoi(segment); err == nil {
+		var array []json.RawMessage
+		if err = json.Unmarshal(raw, &array); err == nil {
+			if index < 0 || index >= len(array) {
+				return nil, PathNotFoundErr
+			}
+			return array[index], nil
+		}
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, PathNotFoundErr
+	}
+
+	value, ok := object[segment]
+	if !ok {
+		return nil, PathNotFoundErr
+	}
+
+	return value, nil
+}
+
+// splitPath splits a path like "data[0].game_name" into its segments "data", "0" and "game_name".
+func splitPath(path string) []string {
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+
+	var segments []string
+	for _, segment := range strings.Split(path, ".") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments
+}
+
+func (s *SearchGame) setRawPayload(raw json.RawMessage) {
+	s.Raw = raw
+}
+
+func (g *gameDetailsResponse) setRawPayload(raw json.RawMessage) {
+	g.raw = raw
+}
@@ -0,0 +1,73 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_WithRawPayloads(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt"}})
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"count":1,"data":[{"game_id":10270,"game_name":"The Witcher 3: Wild Hunt","game_rating":87}]}`))
+	}
+
+	mockClient := server.client(t, WithRawPayloads())
+
+	result, err := mockClient.Search(ctx, "Witcher", SearchModifierNone, nil)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	var rating int
+	if err = result.Lookup("data[0].game_rating", &rating); err != nil || rating != 87 {
+		t.Errorf("Lookup() = %d, %v, want %d", rating, err, 87)
+	}
+
+	details, err := mockClient.Detail(ctx, 10270)
+	if err != nil {
+		t.Fatalf("Detail() error = %v", err)
+	}
+
+	var name string
+	if err = details.Lookup("props.pageProps.game.data.game.0.game_name", &name); err != nil || name != "The Witcher 3: Wild Hunt" {
+		t.Errorf("Lookup() = %q, %v", name, err)
+	}
+}
+
+func Test_Lookup_Errors(t *testing.T) {
+	var v any
+
+	if err := (&SearchGame{}).Lookup("data", &v); !errors.Is(err, RawPayloadUnavailableErr) {
+		t.Errorf(`Lookup() expected "%v" error, but received: %v`, RawPayloadUnavailableErr, err)
+	}
+
+	raw := []byte(`{"data":[{"game_id":1}]}`)
+
+	for _, path := range []string{"count", "data[1]", "data.0.game_id.value", "data.first"} {
+		if err := LookupRaw(raw, path, &v); !errors.Is(err, PathNotFoundErr) {
+			t.Errorf(`LookupRaw(%q) expected "%v" error, but received: %v`, path, PathNotFoundErr, err)
+		}
+	}
+}
+
+func Test_Search_RawPayloadsDisabled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 1, GameName: "Game"}})
+
+	result, err := server.client(t).Search(ctx, "Game", SearchModifierNone, nil)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if result.Raw != nil {
+		t.Errorf("Search() raw = %s, want nil", result.Raw)
+	}
+}
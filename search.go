@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 )
 
 type (
@@ -65,6 +67,12 @@ type (
 		PageTotal   int              `json:"pageTotal"`
 		PageSize    int              `json:"pageSize"`
 		Data        []SearchGameData `json:"data"`
+		// Raw is the search response as returned by HowLongToBeat, if enabled with WithRawPayloads.
+		Raw json.RawMessage `json:"-"`
+		// Stale is set if the result is served from the cache past its time to live, see WithStaleWhileRevalidate.
+		Stale bool `json:"-"`
+		// Age is the time since a cached result was fetched, 0 for fresh results.
+		Age time.Duration `json:"-"`
 	}
 
 	SearchGamePagination struct {
@@ -171,18 +179,15 @@ func (c *Client) normalizePaginationValue(value, defaultVal int) int {
 }
 
 func (c *Client) searchHTTPRequest(ctx context.Context, body []byte, endpoint, token string) (*http.Request, error) {
-	req, err := c.request(ctx, http.MethodPost, hltbBaseURL+"/"+endpoint, bytes.NewBuffer(body))
+	req, err := c.request(ctx, OperationSearch, http.MethodPost, c.url("/"+strings.TrimPrefix(endpoint, "/")), bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set(http.CanonicalHeaderKey("Content-Type"), "application/json")
 	req.Header.Set(http.CanonicalHeaderKey("Accept"), "*/*")
-	req.Header.Set(http.CanonicalHeaderKey("Accept-Language"), "en")
 	req.Header.Set(http.CanonicalHeaderKey("Cache-Control"), "no-cache")
 	req.Header.Set(http.CanonicalHeaderKey("Pragma"), "no-cache")
-	req.Header.Set(http.CanonicalHeaderKey("Sec-Ch-Ua"), `Not;A Brand;v="99", "Google Chrome";v="115", "Chromium";v="115"`)
-	req.Header.Set(http.CanonicalHeaderKey("Sec-Ch-Ua-Mobile"), "?0")
 	req.Header.Set(http.CanonicalHeaderKey("Sec-Fetch-Mode"), "cors")
 	req.Header.Set(http.CanonicalHeaderKey("Sec-Fetch-Dest"), "empty")
 	req.Header.Set(http.CanonicalHeaderKey("Dnt"), "1")
@@ -195,7 +200,7 @@ func (c *Client) searchHTTPRequest(ctx context.Context, body []byte, endpoint, t
 // SearchTerm is typically the title of the game or DLC.
 // SearchModifier can be used to filter the results by either excluding or including games and DLCs.
 // SearchOptions.Pagination is optional, but recommended. The default page size is 20.
-func (c *Client) Search(ctx context.Context, searchTerm string, searchModifier SearchModifier, options *SearchOptions) (*SearchGame, error) {
+func (c *Client) Search(ctx context.Context, searchTerm string, searchModifier SearchModifier, options *SearchOptions) (result *SearchGame, err error) {
 	if searchTerm == "" {
 		return nil, EmptySearchTermErr
 	}
@@ -207,27 +212,85 @@ func (c *Client) Search(ctx context.Context, searchTerm string, searchModifier S
 		}
 	}
 
-	apiData, err := c.getApiData(ctx)
-	if err != nil {
-		return nil, err
+	requestBody := c.prepSearchRequest(searchTerm, searchModifier, options.Pagination)
+	cacheKey := searchCacheKey(searchTerm, searchModifier, requestBody)
+
+	ctx, obs := c.observe(ctx, OperationSearch)
+	info := OperationInfo{Page: requestBody.SearchPage}
+
+	defer func() {
+		if result != nil {
+			info.ResultCount = len(result.Data)
+		}
+		info.Err = err
+		obs.end(info)
+	}()
+
+	cached, age, state := c.cache.get(cacheKey)
+
+	switch state {
+	case cacheFresh, cacheRefresh:
+		if state == cacheRefresh {
+			c.revalidate(ctx, OperationSearch, cacheKey, OperationInfo{Page: info.Page}, func(ctx context.Context, info *OperationInfo) error {
+				result, retries, err := c.fetchSearch(ctx, searchTerm, requestBody, cacheKey)
+				if result != nil {
+					info.ResultCount = len(result.Data)
+				}
+				info.Retries = retries
+				return err
+			})
+		}
+
+		info.CacheHit = true
+		return cached.(*SearchGame).fromCache(age, state == cacheRefresh), nil
 	}
 
-	requestBody := c.prepSearchRequest(searchTerm, searchModifier, options.Pagination)
+	result, info.Retries, err = c.fetchSearch(ctx, searchTerm, requestBody, cacheKey)
+	if err != nil && c.serveStale(ctx, state, cacheKey, age, err) {
+		info.CacheHit = true
+		return cached.(*SearchGame).fromCache(age, true), nil
+	}
 
-	body, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, err
+	return result, err
+}
+
+// fromCache returns a copy of the cached result with its age.
+func (s *SearchGame) fromCache(age time.Duration, stale bool) *SearchGame {
+	result := *s
+	result.Age = age
+	result.Stale = stale
+
+	return &result
+}
+
+// fetchSearch requests the search results from HowLongToBeat, shared with identical calls in flight if enabled with
+// WithRequestCoalescing. It returns the number of retries along with the result.
+func (c *Client) fetchSearch(ctx context.Context, searchTerm string, requestBody *searchRequest, cacheKey string) (*SearchGame, int, error) {
+	type searchFlight struct {
+		result  *SearchGame
+		retries int
 	}
 
-	req, err := c.searchHTTPRequest(ctx, body, apiData.endpointPath, apiData.token)
+	value, err := c.flights.do(ctx, cacheKey, func(ctx context.Context) (any, error) {
+		result, retries, err := c.requestSearch(ctx, searchTerm, requestBody, cacheKey)
+		return searchFlight{result: result, retries: retries}, err
+	})
+
+	flight, _ := value.(searchFlight)
+
+	return flight.result, flight.retries, err
+}
+
+// requestSearch requests the search results from HowLongToBeat and caches them.
+func (c *Client) requestSearch(ctx context.Context, searchTerm string, requestBody *searchRequest, cacheKey string) (*SearchGame, int, error) {
+	body, err := json.Marshal(requestBody)
 	if err != nil {
-		return nil, fmt.Errorf("create search request: %w", err)
+		return nil, 0, err
 	}
 
-	var resp SearchGame
-
-	if err = c.do(req, c.jsonParser(&resp)); err != nil {
-		return nil, fmt.Errorf("search: %w", err)
+	resp, retries, err := c.search(ctx, body)
+	if err != nil {
+		return nil, retries, err
 	}
 
 	var searchResults = make([]SearchGameData, len(resp.Data))
@@ -246,5 +309,57 @@ func (c *Client) Search(ctx context.Context, searchTerm string, searchModifier S
 		})
 	}
 
-	return &resp, nil
+	c.cache.set(cacheKey, resp)
+
+	return resp, retries, nil
+}
+
+// WithSearchRetry retries a search once with a new token if HowLongToBeat rejects it with status 401, 403 or 404.
+// A 404 also switches the client to discovering the search endpoint from the scripts of the homepage, as the endpoint
+// moved. Without it, the StatusError is returned. A 403 can also be a bot challenge, the search is retried only once.
+func WithSearchRetry() Option {
+	return func(client *Client) {
+		client.searchRetry = true
+	}
+}
+
+// search sends the search request. With WithSearchRetry, the api data is refreshed and the request is retried once if
+// HowLongToBeat rejects the token or the endpoint. A rejected endpoint switches the client to endpoint discovery.
+// It returns the number of retries along with the result.
+func (c *Client) search(ctx context.Context, body []byte) (*SearchGame, int, error) {
+	for attempt := 1; ; attempt++ {
+		apiData, err := c.getApiData(ctx)
+		if err != nil {
+			return nil, attempt - 1, err
+		}
+
+		req, err := c.searchHTTPRequest(ctx, body, apiData.endpointPath, apiData.token)
+		if err != nil {
+			return nil, attempt - 1, fmt.Errorf("create search request: %w", err)
+		}
+
+		var resp SearchGame
+
+		err = c.do(req, c.jsonParser(&resp))
+		if err == nil {
+			return &resp, attempt - 1, nil
+		}
+
+		var statusErr *StatusError
+		if !c.searchRetry || attempt > 1 || !errors.As(err, &statusErr) || !isStaleApiData(statusErr.StatusCode) {
+			return nil, attempt - 1, fmt.Errorf("search: %w", err)
+		}
+
+		c.log(ctx, slog.LevelWarn, "search rejected, retrying with fresh api data",
+			slog.Int("status", statusErr.StatusCode),
+			slog.Int("attempt", attempt),
+		)
+
+		c.invalidateApiData(apiData, statusErr.StatusCode == http.StatusNotFound)
+	}
+}
+
+// isStaleApiData reports whether a search status code indicates an expired token or a moved endpoint.
+func isStaleApiData(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden || statusCode == http.StatusNotFound
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -152,16 +153,16 @@ func Test_prepSearchRequest(t *testing.T) {
 func Test_searchHTTPRequest(t *testing.T) {
 	var (
 		headers = map[string]string{
-			http.CanonicalHeaderKey("User-Agent"):       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/115.0.0.0 Safari/537.36",
+			http.CanonicalHeaderKey("User-Agent"):       DefaultHeaderProfile.UserAgent,
 			http.CanonicalHeaderKey("Origin"):           "https://howlongtobeat.com/",
 			http.CanonicalHeaderKey("Referer"):          "https://howlongtobeat.com/",
 			http.CanonicalHeaderKey("Content-Type"):     "application/json",
 			http.CanonicalHeaderKey("Accept"):           "*/*",
-			http.CanonicalHeaderKey("Accept-Language"):  "en",
+			http.CanonicalHeaderKey("Accept-Language"):  DefaultHeaderProfile.AcceptLanguage,
 			http.CanonicalHeaderKey("Cache-Control"):    "no-cache",
 			http.CanonicalHeaderKey("Pragma"):           "no-cache",
-			http.CanonicalHeaderKey("Sec-Ch-Ua"):        `Not;A Brand;v="99", "Google Chrome";v="115", "Chromium";v="115"`,
-			http.CanonicalHeaderKey("Sec-Ch-Ua-Mobile"): "?0",
+			http.CanonicalHeaderKey("Sec-Ch-Ua"):        DefaultHeaderProfile.SecChUa,
+			http.CanonicalHeaderKey("Sec-Ch-Ua-Mobile"): DefaultHeaderProfile.SecChUaMobile,
 			http.CanonicalHeaderKey("Sec-Fetch-Mode"):   "cors",
 			http.CanonicalHeaderKey("Sec-Fetch-Dest"):   "empty",
 			http.CanonicalHeaderKey("Dnt"):              "1",
@@ -230,3 +231,49 @@ func Test_Search_NullSearchTerm(t *testing.T) {
 		t.Fatalf(`Search() expected %v" error, but received: %v`, EmptySearchTermErr, err)
 	}
 }
+
+func Test_WithSearchRetry(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newMockServer(t, mockGame{game: GameDetailsGameDataGame{GameID: 1, GameName: "Game"}})
+
+	var status atomic.Int32
+	status.Store(http.StatusForbidden)
+
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		if server.searchCalls.Load()%2 == 1 {
+			w.WriteHeader(int(status.Load()))
+			return
+		}
+		server.serveSearch(w, r)
+	}
+
+	mockClient := server.client(t, WithSearchRetry())
+
+	// The rejected token is replaced and the search retried once.
+	if _, err := mockClient.Search(ctx, "Game", SearchModifierNone, nil); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if calls := server.tokenCalls.Load(); calls != 2 || mockClient.discoverEndpoint {
+		t.Errorf("Search() fetched %d tokens, discover endpoint = %v, want 2 tokens", calls, mockClient.discoverEndpoint)
+	}
+
+	// A rejected endpoint switches the client to endpoint discovery.
+	status.Store(http.StatusNotFound)
+
+	if _, err := mockClient.Search(ctx, "Game 2", SearchModifierNone, nil); err == nil || !mockClient.discoverEndpoint {
+		t.Errorf("Search() error = %v, discover endpoint = %v, want a failed discovery", err, mockClient.discoverEndpoint)
+	}
+
+	// Without the option, the rejection is returned.
+	var statusErr *StatusError
+
+	status.Store(http.StatusForbidden)
+	server.searchCalls.Store(0)
+
+	if _, err := server.client(t).Search(ctx, "Game", SearchModifierNone, nil); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Search() expected status %d, received: %v", http.StatusForbidden, err)
+	}
+}
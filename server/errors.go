@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+// apiError is the JSON error body returned by all endpoints.
+type apiError struct {
+	status int
+	// retryAfter is sent as Retry-After header in seconds, if set.
+	retryAfter int
+
+	Code           string `json:"code"`
+	Message        string `json:"message"`
+	UpstreamStatus int    `json:"upstream_status,omitempty"`
+}
+
+func invalidRequest(format string, args ...any) *apiError {
+	return &apiError{status: http.StatusBadRequest, Code: "invalid_request", Message: fmt.Sprintf(format, args...)}
+}
+
+// errorFrom maps an error returned by the client to the API error sent to the caller.
+func errorFrom(err error) *apiError {
+	var (
+		statusErr  *howlongtobeat.StatusError
+		circuitErr *howlongtobeat.CircuitOpenError
+	)
+
+	switch {
+	case errIs(err, howlongtobeat.EmptySearchTermErr, howlongtobeat.GameIDRequiredErr):
+		return &apiError{status: http.StatusBadRequest, Code: "invalid_request", Message: err.Error()}
+	case errors.Is(err, howlongtobeat.GameNotFoundErr):
+		return &apiError{status: http.StatusNotFound, Code: "not_found", Message: err.Error()}
+	case errors.As(err, &statusErr):
+		return &apiError{status: http.StatusBadGateway, Code: "upstream_error", Message: err.Error(), UpstreamStatus: statusErr.StatusCode}
+	case errors.As(err, &circuitErr):
+		return &apiError{
+			status:     http.StatusServiceUnavailable,
+			retryAfter: int(math.Ceil(circuitErr.RetryAfter.Seconds())),
+			Code:       "upstream_unavailable",
+			Message:    err.Error(),
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &apiError{status: http.StatusGatewayTimeout, Code: "upstream_timeout", Message: err.Error()}
+	case errors.Is(err, context.Canceled):
+		// The caller went away, the status code is only visible in logs.
+		return &apiError{status: 499, Code: "canceled", Message: err.Error()}
+	default:
+		return &apiError{status: http.StatusBadGateway, Code: "upstream_error", Message: err.Error()}
+	}
+}
+
+// detailErrorFrom maps an error returned by Client.Detail. HowLongToBeat answers unknown game ids with 404,
+// on any other route a 404 is an upstream error.
+func detailErrorFrom(err error) *apiError {
+	var statusErr *howlongtobeat.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return &apiError{status: http.StatusNotFound, Code: "not_found", Message: "game not found", UpstreamStatus: statusErr.StatusCode}
+	}
+
+	return errorFrom(err)
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	if err.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(err.retryAfter))
+	}
+
+	writeJSON(w, err.status, map[string]*apiError{"error": err})
+}
+
+// errIs reports whether any error in err's tree matches one of the targets.
+func errIs(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// rateLimiter is a token bucket rate limiter per client.
+	rateLimiter struct {
+		mu      sync.Mutex
+		rate    float64
+		burst   float64
+		buckets map[string]*bucket
+		swept   time.Time
+	}
+
+	bucket struct {
+		tokens float64
+		last   time.Time
+	}
+)
+
+// idleBucketTimeout is the time after which the bucket of an inactive client is removed.
+const idleBucketTimeout = 10 * time.Minute
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		swept:   time.Now(),
+	}
+}
+
+// allow takes a token from the bucket of the client. If the bucket is empty, it returns false and the duration until
+// the next token is available.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.swept) > idleBucketTimeout {
+		for k, b := range l.buckets {
+			if now.Sub(b.last) > idleBucketTimeout {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+
+	b.tokens--
+
+	return true, 0
+}
+
+// limit wraps the handler with the rate limiter of the server, if configured.
+func (s *Server) limit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.limiter != nil {
+			ok, wait := s.limiter.allow(s.clientKey(r), time.Now())
+			if s.rateHook != nil {
+				s.rateHook(ok, wait)
+			}
+
+			if !ok {
+				writeError(w, &apiError{
+					status:     http.StatusTooManyRequests,
+					retryAfter: int(math.Ceil(wait.Seconds())),
+					Code:       "rate_limited",
+					Message:    "too many requests",
+				})
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_rateLimiter(t *testing.T) {
+	limiter := newRateLimiter(1, 2)
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.allow("a", now); !ok {
+			t.Fatalf("allow() = false within burst")
+		}
+	}
+
+	ok, wait := limiter.allow("a", now)
+	if ok || wait != time.Second {
+		t.Fatalf("allow() = %v, %v, want false, 1s", ok, wait)
+	}
+
+	if ok, _ = limiter.allow("b", now); !ok {
+		t.Fatalf("allow() = false for a different client")
+	}
+
+	if ok, _ = limiter.allow("a", now.Add(time.Second)); !ok {
+		t.Fatalf("allow() = false after refill")
+	}
+}
@@ -0,0 +1,282 @@
+// Package server exposes a howlongtobeat.Client as a JSON REST API.
+//
+// Endpoints:
+//
+//	GET /search?q=<term>&modifier=<only_dlc|hide_dlc>&page=<page>&size=<size>[&simple=true]
+//	GET /games/<id>[?simple=true]
+//	GET /healthz
+//	GET /readyz
+//	GET /metrics (if configured with WithMetrics)
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+// defaultMaxPageSize is the largest page size accepted by the search endpoint without WithMaxPageSize.
+const defaultMaxPageSize = 100
+
+type (
+	// Server is an http.Handler serving search results and game details of a shared Client.
+	Server struct {
+		client    *howlongtobeat.Client
+		mux       *http.ServeMux
+		limiter   *rateLimiter
+		clientKey func(r *http.Request) string
+		readiness func(ctx context.Context) error
+		rateHook  func(allowed bool, wait time.Duration)
+		metrics   http.Handler
+		maxSize   int
+		draining  atomic.Bool
+	}
+
+	// Option is a type alias for functions to configure your Server.
+	Option func(server *Server)
+)
+
+// WithRateLimit limits each client to rate requests per second with bursts of up to burst requests.
+// Clients are identified by their remote IP address, unless WithClientKey is used.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(server *Server) {
+		if rate > 0 {
+			server.limiter = newRateLimiter(rate, burst)
+		}
+	}
+}
+
+// WithClientKey sets the function identifying a client for rate limiting, e.g. by an API key header or the
+// X-Forwarded-For header when running behind a trusted reverse proxy.
+func WithClientKey(clientKey func(r *http.Request) string) Option {
+	return func(server *Server) {
+		server.clientKey = clientKey
+	}
+}
+
+// WithReadinessCheck sets an additional check for the readiness endpoint, e.g. to verify the connection to
+// HowLongToBeat.
+func WithReadinessCheck(check func(ctx context.Context) error) Option {
+	return func(server *Server) {
+		server.readiness = check
+	}
+}
+
+// WithRateLimitHook sets a function that is called with every decision of the rate limiter, e.g. to record metrics.
+// wait is the time until the client may send its next request.
+func WithRateLimitHook(hook func(allowed bool, wait time.Duration)) Option {
+	return func(server *Server) {
+		server.rateHook = hook
+	}
+}
+
+// WithMetrics serves the given handler on /metrics, e.g. a hltbprom.Collector. The endpoint is not rate limited.
+func WithMetrics(handler http.Handler) Option {
+	return func(server *Server) {
+		server.metrics = handler
+	}
+}
+
+// WithMaxPageSize sets the largest page size accepted by the search endpoint, larger sizes are rejected with 400.
+// The default is 100.
+func WithMaxPageSize(size int) Option {
+	return func(server *Server) {
+		if size > 0 {
+			server.maxSize = size
+		}
+	}
+}
+
+// New creates a new Server backed by the given client. Use howlongtobeat.WithCache on the client to cache responses.
+func New(client *howlongtobeat.Client, options ...Option) *Server {
+	s := &Server{
+		client:    client,
+		mux:       http.NewServeMux(),
+		clientKey: remoteIP,
+		maxSize:   defaultMaxPageSize,
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	s.mux.HandleFunc("/search", s.limit(s.handleSearch))
+	s.mux.HandleFunc("/games/", s.limit(s.handleGame))
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.HandleFunc("/readyz", s.handleReady)
+
+	if s.metrics != nil {
+		s.mux.Handle("/metrics", s.metrics)
+	}
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, &apiError{status: http.StatusMethodNotAllowed, Code: "method_not_allowed", Message: "only GET requests are supported"})
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// Drain marks the server as not ready, so load balancers stop sending new requests before it shuts down.
+func (s *Server) Drain() {
+	s.draining.Store(true)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	modifier := q.Get("modifier")
+	if modifier != howlongtobeat.SearchModifierNone && modifier != howlongtobeat.SearchModifierOnlyDLC && modifier != howlongtobeat.SearchModifierHideDLC {
+		writeError(w, invalidRequest("invalid modifier %q", modifier))
+		return
+	}
+
+	page, err := intParam(q.Get("page"))
+	if err != nil {
+		writeError(w, invalidRequest("invalid page %q", q.Get("page")))
+		return
+	}
+
+	size, err := intParam(q.Get("size"))
+	if err != nil {
+		writeError(w, invalidRequest("invalid size %q", q.Get("size")))
+		return
+	}
+
+	if size > s.maxSize {
+		writeError(w, invalidRequest("size %d exceeds the maximum of %d", size, s.maxSize))
+		return
+	}
+
+	simple, err := boolParam(q.Get("simple"))
+	if err != nil {
+		writeError(w, invalidRequest("invalid simple %q", q.Get("simple")))
+		return
+	}
+
+	result, err := s.client.Search(r.Context(), strings.TrimSpace(q.Get("q")), modifier, &howlongtobeat.SearchOptions{
+		Pagination: &howlongtobeat.SearchGamePagination{Page: page, PageSize: size},
+	})
+	if err != nil {
+		writeError(w, errorFrom(err))
+		return
+	}
+
+	setCacheHeaders(w, result.Age, result.Stale)
+
+	if simple {
+		writeJSON(w, http.StatusOK, result.Reduce())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleGame(w http.ResponseWriter, r *http.Request) {
+	gameID, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(r.URL.Path, "/games/"), "/"))
+	if err != nil || gameID <= 0 {
+		writeError(w, &apiError{status: http.StatusNotFound, Code: "not_found", Message: "invalid game id"})
+		return
+	}
+
+	simple, err := boolParam(r.URL.Query().Get("simple"))
+	if err != nil {
+		writeError(w, invalidRequest("invalid simple %q", r.URL.Query().Get("simple")))
+		return
+	}
+
+	details, err := s.client.Detail(r.Context(), gameID)
+	if err != nil {
+		writeError(w, detailErrorFrom(err))
+		return
+	}
+
+	if len(details.Props.PageProps.Game.Data.Game) == 0 {
+		writeError(w, errorFrom(howlongtobeat.GameNotFoundErr))
+		return
+	}
+
+	setCacheHeaders(w, details.Age, details.Stale)
+
+	if simple {
+		writeJSON(w, http.StatusOK, details.Reduce())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, details)
+}
+
+// setCacheHeaders sets the Age header of cached results, and the X-Stale header of stale results.
+func setCacheHeaders(w http.ResponseWriter, age time.Duration, stale bool) {
+	if age > 0 {
+		w.Header().Set("Age", strconv.Itoa(int(age.Seconds())))
+	}
+
+	if stale {
+		w.Header().Set("X-Stale", "true")
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if s.draining.Load() {
+		writeError(w, &apiError{status: http.StatusServiceUnavailable, Code: "draining", Message: "server is shutting down"})
+		return
+	}
+
+	if s.readiness != nil {
+		if err := s.readiness(r.Context()); err != nil {
+			writeError(w, &apiError{status: http.StatusServiceUnavailable, Code: "not_ready", Message: err.Error()})
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+func intParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+func boolParam(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func remoteIP(r *http.Request) string {
+	host := r.RemoteAddr
+	if i := strings.LastIndexByte(host, ':'); i != -1 {
+		host = host[:i]
+	}
+
+	return strings.Trim(host, "[]")
+}
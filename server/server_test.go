@@ -0,0 +1,326 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+func newTestServer(t *testing.T, options ...Option) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	upstreamCalls := &atomic.Int32{}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/finder/init":
+			_, _ = w.Write([]byte(`{"token":"token"}`))
+		case r.URL.Path == "/api/finder":
+			upstreamCalls.Add(1)
+			if body, _ := io.ReadAll(r.Body); strings.Contains(string(body), "removed") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(howlongtobeat.SearchGame{
+				Data: []howlongtobeat.SearchGameData{{GameID: 10270, GameName: "The Witcher 3", CompMain: 36000}},
+			})
+		case r.URL.Path == "/game/10270":
+			upstreamCalls.Add(1)
+			_, _ = fmt.Fprint(w, `<script id="__NEXT_DATA__" type="application/json">`+
+				`{"props":{"pageProps":{"game":{"data":{"game":[{"game_id":10270,"game_name":"The Witcher 3","comp_main":36000}]}},"ignWikiNav":[]}}}`+
+				`</script>`)
+		case r.URL.Path == "/game/503":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	client, err := howlongtobeat.New(howlongtobeat.WithBaseURL(upstream.URL), howlongtobeat.WithCache(time.Minute, 0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	server := httptest.NewServer(New(client, options...))
+	t.Cleanup(server.Close)
+
+	return server, upstreamCalls
+}
+
+func get(t *testing.T, url string, body any) *http.Response {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	if body != nil {
+		if err = json.NewDecoder(resp.Body).Decode(body); err != nil {
+			t.Fatalf("decode response of %s: %v", url, err)
+		}
+	}
+
+	return resp
+}
+
+func TestServer_Search(t *testing.T) {
+	server, upstreamCalls := newTestServer(t)
+
+	var result howlongtobeat.SearchGame
+	if resp := get(t, server.URL+"/search?q=witcher&page=1&size=5", &result); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /search status = %d", resp.StatusCode)
+	}
+
+	if len(result.Data) != 1 || result.Data[0].GameID != 10270 {
+		t.Fatalf("GET /search = %+v", result)
+	}
+
+	var simple []howlongtobeat.SearchGameSimple
+	resp := get(t, server.URL+"/search?q=witcher&page=1&size=5&simple=true", &simple)
+
+	if len(simple) != 1 || simple[0].CompMain != 10 {
+		t.Fatalf("GET /search?simple=true = %+v", simple)
+	}
+
+	if resp.Header.Get("Age") == "" || resp.Header.Get("X-Stale") != "" {
+		t.Errorf("GET /search?simple=true Age = %q, X-Stale = %q, want a fresh cached response", resp.Header.Get("Age"), resp.Header.Get("X-Stale"))
+	}
+
+	if calls := upstreamCalls.Load(); calls != 1 {
+		t.Errorf("upstream called %d times, want cached response", calls)
+	}
+}
+
+func TestServer_Game(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var details howlongtobeat.GameDetails
+	if resp := get(t, server.URL+"/games/10270", &details); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /games/10270 status = %d", resp.StatusCode)
+	}
+
+	if details.Props.PageProps.Game.Data.Game[0].GameID != 10270 {
+		t.Fatalf("GET /games/10270 = %+v", details)
+	}
+
+	var simple howlongtobeat.GameDetailSimple
+	get(t, server.URL+"/games/10270?simple=1", &simple)
+
+	if simple.GameName != "The Witcher 3" {
+		t.Fatalf("GET /games/10270?simple=1 = %+v", simple)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"/search", http.StatusBadRequest, "invalid_request"},
+		{"/search?q=witcher&modifier=dlc", http.StatusBadRequest, "invalid_request"},
+		{"/search?q=witcher&page=abc", http.StatusBadRequest, "invalid_request"},
+		{"/search?q=witcher&size=101", http.StatusBadRequest, "invalid_request"},
+		{"/search?q=removed", http.StatusBadGateway, "upstream_error"},
+		{"/games/abc", http.StatusNotFound, "not_found"},
+		{"/games/2", http.StatusNotFound, "not_found"},
+		{"/games/503", http.StatusBadGateway, "upstream_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var body struct {
+				Error apiError `json:"error"`
+			}
+
+			resp := get(t, server.URL+tt.path, &body)
+
+			if resp.StatusCode != tt.wantStatus || body.Error.Code != tt.wantCode {
+				t.Errorf("GET %s = %d %+v, want %d %s", tt.path, resp.StatusCode, body.Error, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+
+	resp, err := http.Post(server.URL+"/search", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("POST /search: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST /search status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	server, _ := newTestServer(t, WithRateLimit(0.001, 2))
+
+	for i := 0; i < 2; i++ {
+		if resp := get(t, server.URL+"/games/10270", nil); resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET /games/10270 status = %d", resp.StatusCode)
+		}
+	}
+
+	resp := get(t, server.URL+"/games/10270", nil)
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("GET /games/10270 status = %d, Retry-After = %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+
+	// Health checks are not rate limited.
+	if resp = get(t, server.URL+"/healthz", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /healthz status = %d", resp.StatusCode)
+	}
+}
+
+func TestServer_RateLimitHook(t *testing.T) {
+	var (
+		allowed, rejected int
+		lastWait          time.Duration
+	)
+
+	server, _ := newTestServer(t, WithRateLimit(0.001, 1), WithRateLimitHook(func(ok bool, wait time.Duration) {
+		if ok {
+			allowed++
+		} else {
+			rejected++
+		}
+		lastWait = wait
+	}))
+
+	for i := 0; i < 2; i++ {
+		get(t, server.URL+"/games/10270", nil)
+	}
+
+	if allowed != 1 || rejected != 1 || lastWait <= 0 {
+		t.Fatalf("WithRateLimitHook() allowed = %d, rejected = %d, wait = %v", allowed, rejected, lastWait)
+	}
+}
+
+func TestServer_CircuitHalfOpen(t *testing.T) {
+	var (
+		searchCalls atomic.Int32
+		probing     = make(chan struct{})
+		release     = make(chan struct{})
+	)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/finder/init":
+			_, _ = w.Write([]byte(`{"token":"token"}`))
+		case "/api/finder":
+			if searchCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			close(probing)
+			<-release
+			_ = json.NewEncoder(w).Encode(howlongtobeat.SearchGame{})
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	client, err := howlongtobeat.New(
+		howlongtobeat.WithBaseURL(upstream.URL),
+		howlongtobeat.WithCircuitBreaker(&howlongtobeat.CircuitBreakerOptions{ConsecutiveFailures: 1, Cooldown: 50 * time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	server := httptest.NewServer(New(client))
+	t.Cleanup(server.Close)
+
+	if resp := get(t, server.URL+"/search?q=witcher", nil); resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("GET /search status = %d, want %d", resp.StatusCode, http.StatusBadGateway)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	probed := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(server.URL + "/search?q=zelda")
+		if err != nil {
+			probed <- 0
+			return
+		}
+		_ = resp.Body.Close()
+		probed <- resp.StatusCode
+	}()
+
+	select {
+	case <-probing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("probe request did not reach the upstream")
+	}
+
+	// The probe is still in flight, so the circuit rejects further requests while half-open.
+	resp := get(t, server.URL+"/search?q=mario", nil)
+	close(release)
+
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") != "1" {
+		t.Errorf("GET /search while half-open status = %d, Retry-After = %q, want %d and 1", resp.StatusCode, resp.Header.Get("Retry-After"), http.StatusServiceUnavailable)
+	}
+
+	if status := <-probed; status != http.StatusOK {
+		t.Errorf("probe status = %d, want %d", status, http.StatusOK)
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hltb_metric 1\n"))
+	})
+
+	server, _ := newTestServer(t, WithRateLimit(0.001, 1), WithMetrics(metrics))
+
+	// The metrics endpoint is not rate limited.
+	for i := 0; i < 3; i++ {
+		if resp := get(t, server.URL+"/metrics", nil); resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET /metrics status = %d", resp.StatusCode)
+		}
+	}
+
+	if resp := get(t, server.URL+"/metrics-missing", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("GET /metrics-missing status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestServer_Ready(t *testing.T) {
+	ready := errors.New("not connected")
+
+	handler := New(&howlongtobeat.Client{}, WithReadinessCheck(func(ctx context.Context) error { return ready }))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	if resp := get(t, server.URL+"/readyz", nil); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("GET /readyz status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	ready = nil
+
+	if resp := get(t, server.URL+"/readyz", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /readyz status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	handler.Drain()
+
+	if resp := get(t, server.URL+"/readyz", nil); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("GET /readyz status = %d after Drain(), want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+}
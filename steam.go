@@ -0,0 +1,97 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// steamDetailCandidates is the number of search results whose details are fetched to verify a Steam app ID,
+// if none of the search results carries the app ID directly.
+const steamDetailCandidates = 5
+
+var (
+	SteamAppIDRequiredErr = errors.New("steam app id is required")
+	SteamGameNotFoundErr  = errors.New("no game found for steam app id")
+)
+
+// FindBySteamID returns the game matching the given Steam app ID.
+// TitleHint is used as the search term, typically the title of the game on Steam.
+// Search results are verified against ProfileSteam first; if none matches, the details of the most similar results
+// are fetched to compare ProfileSteam and ProfileSteamAlt.
+// Matches are cached on the client, so subsequent lookups of the same app ID do not hit HowLongToBeat.
+// Candidates whose details cannot be fetched are skipped. If no game matches, SteamGameNotFoundErr will be returned,
+// or the joined errors of the skipped candidates if any.
+func (c *Client) FindBySteamID(ctx context.Context, steamAppID int, titleHint string) (*SearchGameData, error) {
+	if steamAppID == 0 {
+		return nil, SteamAppIDRequiredErr
+	}
+
+	if game, ok := c.cachedSteamID(steamAppID); ok {
+		return &game, nil
+	}
+
+	result, err := c.Search(ctx, titleHint, SearchModifierNone, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, game := range result.Data {
+		if game.ProfileSteam == steamAppID {
+			c.cacheSteamID(steamAppID, game)
+			return &game, nil
+		}
+	}
+
+	var errs []error
+
+	for i, game := range result.Data {
+		if i == steamDetailCandidates {
+			break
+		}
+
+		details, err := c.Detail(ctx, game.GameID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			// A candidate that cannot be verified must not hide a match among the others.
+			errs = append(errs, fmt.Errorf("verify game %d: %w", game.GameID, err))
+			continue
+		}
+
+		for _, detail := range details.Props.PageProps.Game.Data.Game {
+			if detail.ProfileSteam == steamAppID || detail.ProfileSteamAlt == steamAppID {
+				c.cacheSteamID(steamAppID, game)
+				return &game, nil
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return nil, SteamGameNotFoundErr
+}
+
+func (c *Client) cachedSteamID(steamAppID int) (SearchGameData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	game, ok := c.steamIDs[steamAppID]
+
+	return game, ok
+}
+
+func (c *Client) cacheSteamID(steamAppID int, game SearchGameData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.steamIDs == nil {
+		c.steamIDs = make(map[int]SearchGameData)
+	}
+
+	c.steamIDs[steamAppID] = game
+}
@@ -0,0 +1,165 @@
+// Package steam reads the apps installed in local Steam library folders and resolves them to HowLongToBeat games.
+package steam
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+type (
+	// App is a Steam app installed in one of the Steam library folders.
+	App struct {
+		AppID       int    `json:"app_id"`
+		Name        string `json:"name"`
+		InstallDir  string `json:"install_dir"`
+		LibraryPath string `json:"library_path"`
+	}
+
+	// SkippedManifest is an appmanifest_*.acf file that could not be read, e.g. one Steam is writing while it updates
+	// the app.
+	SkippedManifest struct {
+		Path string `json:"path"`
+		// Error is the message of Err.
+		Error string `json:"error"`
+		Err   error  `json:"-"`
+	}
+)
+
+// LibraryFolders returns the paths of all Steam library folders listed in the libraryfolders.vdf of the given Steam
+// installation directory. The installation directory itself is always returned as the first library folder.
+func LibraryFolders(steamDir string) ([]string, error) {
+	folders := []string{steamDir}
+
+	f, err := os.Open(filepath.Join(steamDir, "steamapps", "libraryfolders.vdf"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return folders, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	doc, err := ParseVDF(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse libraryfolders.vdf: %w", err)
+	}
+
+	seen := map[string]bool{filepath.Clean(steamDir): true}
+
+	for _, entry := range doc.Get("libraryfolders").Children {
+		// Older Steam clients list the path as value of a numeric key, newer ones use a nested "path" key.
+		path := entry.Value
+		if len(entry.Children) > 0 {
+			path = entry.String("path")
+		} else if _, err = strconv.Atoi(entry.Key); err != nil {
+			continue
+		}
+
+		if path == "" || seen[filepath.Clean(path)] {
+			continue
+		}
+		seen[filepath.Clean(path)] = true
+
+		folders = append(folders, path)
+	}
+
+	return folders, nil
+}
+
+// LibraryApps returns the apps of a single Steam library folder by parsing its appmanifest_*.acf files.
+// Manifests that cannot be read or parsed are skipped and returned separately, the other apps are still returned.
+func LibraryApps(libraryDir string) ([]App, []SkippedManifest, error) {
+	manifests, err := filepath.Glob(filepath.Join(libraryDir, "steamapps", "appmanifest_*.acf"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.Strings(manifests)
+
+	var (
+		apps    = make([]App, 0, len(manifests))
+		skipped []SkippedManifest
+	)
+
+	for _, manifest := range manifests {
+		app, err := readAppManifest(manifest)
+		if err != nil {
+			skipped = append(skipped, SkippedManifest{Path: manifest, Error: err.Error(), Err: err})
+			continue
+		}
+
+		app.LibraryPath = libraryDir
+		apps = append(apps, app)
+	}
+
+	return apps, skipped, nil
+}
+
+// InstalledApps returns the apps of all library folders of the given Steam installation directory, e.g.
+// `C:\Program Files (x86)\Steam` or `~/.steam/steam`. Unreadable manifests are skipped, see LibraryApps.
+func InstalledApps(steamDir string) ([]App, []SkippedManifest, error) {
+	folders, err := LibraryFolders(steamDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		apps    []App
+		skipped []SkippedManifest
+		seen    = make(map[int]bool)
+	)
+
+	for _, folder := range folders {
+		libraryApps, librarySkipped, err := LibraryApps(folder)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		skipped = append(skipped, librarySkipped...)
+
+		for _, app := range libraryApps {
+			if seen[app.AppID] {
+				continue
+			}
+			seen[app.AppID] = true
+
+			apps = append(apps, app)
+		}
+	}
+
+	return apps, skipped, nil
+}
+
+func readAppManifest(path string) (App, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return App{}, err
+	}
+	defer f.Close()
+
+	doc, err := ParseVDF(f)
+	if err != nil {
+		return App{}, err
+	}
+
+	state := doc.Get("AppState")
+	if state == nil {
+		return App{}, errors.New("missing AppState")
+	}
+
+	appID, err := strconv.Atoi(state.String("appid"))
+	if err != nil {
+		return App{}, fmt.Errorf("invalid appid: %w", err)
+	}
+
+	return App{
+		AppID:      appID,
+		Name:       state.String("name"),
+		InstallDir: state.String("installdir"),
+	}, nil
+}
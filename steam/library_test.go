@@ -0,0 +1,106 @@
+package steam
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("create directory: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+}
+
+func writeManifest(t *testing.T, libraryDir, appID, name string) {
+	t.Helper()
+
+	writeFile(t, filepath.Join(libraryDir, "steamapps", "appmanifest_"+appID+".acf"),
+		`"AppState" { "appid" "`+appID+`" "name" "`+name+`" "installdir" "`+name+`" }`)
+}
+
+func TestInstalledApps(t *testing.T) {
+	steamDir := t.TempDir()
+	secondLibrary := t.TempDir()
+
+	writeFile(t, filepath.Join(steamDir, "steamapps", "libraryfolders.vdf"), `
+"libraryfolders"
+{
+	"0"
+	{
+		"path"		"`+filepath.ToSlash(steamDir)+`"
+		"apps" { "292030" "0" }
+	}
+	"1"
+	{
+		"path"		"`+filepath.ToSlash(secondLibrary)+`"
+	}
+}`)
+	writeManifest(t, steamDir, "292030", "The Witcher 3: Wild Hunt")
+	writeManifest(t, secondLibrary, "1091500", "Cyberpunk 2077")
+	// A manifest Steam is still writing is skipped.
+	writeFile(t, filepath.Join(secondLibrary, "steamapps", "appmanifest_1245620.acf"), `"AppState" { "appid" "1245620"`)
+
+	folders, err := LibraryFolders(steamDir)
+	if err != nil {
+		t.Fatalf("LibraryFolders() error = %v", err)
+	}
+
+	if want := []string{steamDir, filepath.ToSlash(secondLibrary)}; !reflect.DeepEqual(folders, want) {
+		t.Fatalf("LibraryFolders() = %v, want %v", folders, want)
+	}
+
+	apps, skipped, err := InstalledApps(steamDir)
+	if err != nil {
+		t.Fatalf("InstalledApps() error = %v", err)
+	}
+
+	if len(apps) != 2 || apps[0].AppID != 292030 || apps[1].Name != "Cyberpunk 2077" {
+		t.Fatalf("InstalledApps() = %+v", apps)
+	}
+
+	if len(skipped) != 1 || filepath.Base(skipped[0].Path) != "appmanifest_1245620.acf" || skipped[0].Err == nil {
+		t.Fatalf("InstalledApps() skipped = %+v", skipped)
+	}
+}
+
+func TestLibraryFolders_LegacyFormat(t *testing.T) {
+	steamDir := t.TempDir()
+
+	writeFile(t, filepath.Join(steamDir, "steamapps", "libraryfolders.vdf"), `
+"LibraryFolders"
+{
+	"TimeNextStatsReport"		"1700000000"
+	"ContentStatsID"		"-1234"
+	"1"		"D:\\SteamLibrary"
+}`)
+
+	folders, err := LibraryFolders(steamDir)
+	if err != nil {
+		t.Fatalf("LibraryFolders() error = %v", err)
+	}
+
+	if want := []string{steamDir, `D:\SteamLibrary`}; !reflect.DeepEqual(folders, want) {
+		t.Fatalf("LibraryFolders() = %v, want %v", folders, want)
+	}
+}
+
+func TestLibraryFolders_Missing(t *testing.T) {
+	steamDir := t.TempDir()
+
+	folders, err := LibraryFolders(steamDir)
+	if err != nil {
+		t.Fatalf("LibraryFolders() error = %v", err)
+	}
+
+	if len(folders) != 1 || folders[0] != steamDir {
+		t.Fatalf("LibraryFolders() = %v, want only %v", folders, steamDir)
+	}
+}
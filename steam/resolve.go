@@ -0,0 +1,59 @@
+package steam
+
+import (
+	"context"
+	"errors"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+type (
+	// Match is an installed Steam app and the HowLongToBeat game it was resolved to.
+	Match struct {
+		App  App                           `json:"app"`
+		Game *howlongtobeat.SearchGameData `json:"game"`
+	}
+
+	// Report contains the result of resolving Steam apps to HowLongToBeat games.
+	Report struct {
+		Matched   []Match `json:"matched"`
+		Unmatched []App   `json:"unmatched"`
+		// Skipped lists the manifests ResolveInstalled could not read.
+		Skipped []SkippedManifest `json:"skipped,omitempty"`
+	}
+)
+
+// Resolve resolves each app to a HowLongToBeat game with Client.FindBySteamID, using the app name as title hint.
+// Apps without a matching game are reported as unmatched.
+// Any other error aborts the resolution, the report contains the apps resolved so far.
+func Resolve(ctx context.Context, client *howlongtobeat.Client, apps []App) (*Report, error) {
+	report := &Report{}
+
+	for _, app := range apps {
+		game, err := client.FindBySteamID(ctx, app.AppID, app.Name)
+		switch {
+		case err == nil:
+			report.Matched = append(report.Matched, Match{App: app, Game: game})
+		case errors.Is(err, howlongtobeat.SteamGameNotFoundErr), errors.Is(err, howlongtobeat.EmptySearchTermErr):
+			report.Unmatched = append(report.Unmatched, app)
+		default:
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// ResolveInstalled resolves all apps installed in the given Steam installation directory.
+// Manifests that cannot be read are reported as skipped.
+func ResolveInstalled(ctx context.Context, client *howlongtobeat.Client, steamDir string) (*Report, error) {
+	apps, skipped, err := InstalledApps(steamDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := Resolve(ctx, client, apps)
+	report.Skipped = skipped
+
+	return report, err
+}
@@ -0,0 +1,73 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forbiddencoding/howlongtobeat"
+)
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (r *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestResolve(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/init") {
+			_, _ = w.Write([]byte(`{"token":"token"}`))
+			return
+		}
+
+		var result howlongtobeat.SearchGame
+
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "Witcher") {
+			result.Data = []howlongtobeat.SearchGameData{{GameID: 10270, GameName: "The Witcher 3: Wild Hunt", ProfileSteam: 292030}}
+		}
+
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client, err := howlongtobeat.New(howlongtobeat.WithHTTPClient(&http.Client{Transport: &rewriteTransport{target: target}}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	report, err := Resolve(ctx, client, []App{
+		{AppID: 292030, Name: "The Witcher 3: Wild Hunt"},
+		{AppID: 228980, Name: "Steamworks Common Redistributables"},
+		{AppID: 1},
+	})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if len(report.Matched) != 1 || report.Matched[0].Game.GameID != 10270 {
+		t.Errorf("Resolve() matched = %+v", report.Matched)
+	}
+
+	if len(report.Unmatched) != 2 {
+		t.Errorf("Resolve() unmatched = %+v, want 2 apps", report.Unmatched)
+	}
+}
@@ -0,0 +1,235 @@
+package steam
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KeyValues is a node of a Valve KeyValues document, the text format used by libraryfolders.vdf and the
+// appmanifest_*.acf files. A node either has a Value or Children.
+type KeyValues struct {
+	Key      string
+	Value    string
+	Children []*KeyValues
+}
+
+var UnexpectedEOFErr = errors.New("vdf: unexpected end of file")
+
+// ParseVDF parses a KeyValues document. The returned root node has no key and contains the top-level entries as
+// its children.
+func ParseVDF(r io.Reader) (*KeyValues, error) {
+	p := &vdfParser{r: bufio.NewReader(r)}
+
+	root := &KeyValues{}
+	if err := p.parseChildren(root, false); err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// Get returns the first child with the given key, compared case-insensitively, or nil if there is none.
+func (kv *KeyValues) Get(key string) *KeyValues {
+	if kv == nil {
+		return nil
+	}
+
+	for _, child := range kv.Children {
+		if strings.EqualFold(child.Key, key) {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// String returns the value of the child with the given key, or an empty string if there is none.
+func (kv *KeyValues) String(key string) string {
+	if child := kv.Get(key); child != nil {
+		return child.Value
+	}
+
+	return ""
+}
+
+type (
+	vdfTokenKind int
+
+	vdfToken struct {
+		kind  vdfTokenKind
+		value string
+	}
+
+	vdfParser struct {
+		r    *bufio.Reader
+		peek *vdfToken
+	}
+)
+
+const (
+	vdfTokenString vdfTokenKind = iota
+	vdfTokenOpen
+	vdfTokenClose
+	vdfTokenConditional
+	vdfTokenEOF
+)
+
+func (p *vdfParser) parseChildren(parent *KeyValues, nested bool) error {
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return err
+		}
+
+		switch tok.kind {
+		case vdfTokenEOF:
+			if nested {
+				return UnexpectedEOFErr
+			}
+			return nil
+		case vdfTokenClose:
+			if !nested {
+				return errors.New("vdf: unexpected '}'")
+			}
+			return nil
+		case vdfTokenString:
+			child := &KeyValues{Key: tok.value}
+			if err = p.parseValue(child); err != nil {
+				return err
+			}
+			parent.Children = append(parent.Children, child)
+		default:
+			return fmt.Errorf("vdf: unexpected token %q", tok.value)
+		}
+	}
+}
+
+func (p *vdfParser) parseValue(kv *KeyValues) error {
+	tok, err := p.next()
+	if err != nil {
+		return err
+	}
+
+	switch tok.kind {
+	case vdfTokenString:
+		kv.Value = tok.value
+	case vdfTokenOpen:
+		if err = p.parseChildren(kv, true); err != nil {
+			return err
+		}
+	case vdfTokenEOF:
+		return UnexpectedEOFErr
+	default:
+		return fmt.Errorf("vdf: unexpected token %q after key %q", tok.value, kv.Key)
+	}
+
+	// Platform conditionals like [$WIN32] may follow a value, they are ignored.
+	next, err := p.next()
+	if err != nil {
+		return err
+	}
+
+	if next.kind != vdfTokenConditional {
+		p.peek = &next
+	}
+
+	return nil
+}
+
+func (p *vdfParser) next() (vdfToken, error) {
+	if p.peek != nil {
+		tok := *p.peek
+		p.peek = nil
+		return tok, nil
+	}
+
+	for {
+		r, _, err := p.r.ReadRune()
+		if errors.Is(err, io.EOF) {
+			return vdfToken{kind: vdfTokenEOF}, nil
+		}
+		if err != nil {
+			return vdfToken{}, err
+		}
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\r' || r == '\n':
+			continue
+		case r == '{':
+			return vdfToken{kind: vdfTokenOpen, value: "{"}, nil
+		case r == '}':
+			return vdfToken{kind: vdfTokenClose, value: "}"}, nil
+		case r == '"':
+			value, err := p.readQuoted()
+			return vdfToken{kind: vdfTokenString, value: value}, err
+		case r == '/':
+			if next, _, err := p.r.ReadRune(); err == nil && next == '/' {
+				_, _ = p.r.ReadString('\n')
+				continue
+			}
+			return vdfToken{}, errors.New("vdf: unexpected '/'")
+		case r == '[':
+			value, err := p.r.ReadString(']')
+			if err != nil {
+				return vdfToken{}, UnexpectedEOFErr
+			}
+			return vdfToken{kind: vdfTokenConditional, value: "[" + value}, nil
+		default:
+			_ = p.r.UnreadRune()
+			return vdfToken{kind: vdfTokenString, value: p.readUnquoted()}, nil
+		}
+	}
+}
+
+func (p *vdfParser) readQuoted() (string, error) {
+	var sb strings.Builder
+
+	for {
+		r, _, err := p.r.ReadRune()
+		if err != nil {
+			return "", UnexpectedEOFErr
+		}
+
+		switch r {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			escaped, _, err := p.r.ReadRune()
+			if err != nil {
+				return "", UnexpectedEOFErr
+			}
+
+			switch escaped {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			default:
+				sb.WriteRune(escaped)
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+func (p *vdfParser) readUnquoted() string {
+	var sb strings.Builder
+
+	for {
+		r, _, err := p.r.ReadRune()
+		if err != nil {
+			return sb.String()
+		}
+
+		if r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == '{' || r == '}' || r == '"' {
+			_ = p.r.UnreadRune()
+			return sb.String()
+		}
+
+		sb.WriteRune(r)
+	}
+}
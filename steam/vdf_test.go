@@ -0,0 +1,58 @@
+package steam
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseVDF(t *testing.T) {
+	doc, err := ParseVDF(strings.NewReader(`
+// comment
+"AppState"
+{
+	"appid"		"292030"
+	"name"		"The Witcher 3: Wild Hunt"
+	"installdir"	"The Witcher 3"
+	"UserConfig"
+	{
+		"language"		"english"
+	}
+	"path"		"C:\\Games\\Steam"
+	unquoted	value [$WIN32]
+}
+`))
+	if err != nil {
+		t.Fatalf("ParseVDF() error = %v", err)
+	}
+
+	state := doc.Get("appstate")
+	if state == nil {
+		t.Fatal("ParseVDF() missing AppState")
+	}
+
+	tests := map[string]string{
+		"appid":      "292030",
+		"name":       "The Witcher 3: Wild Hunt",
+		"installdir": "The Witcher 3",
+		"path":       `C:\Games\Steam`,
+		"unquoted":   "value",
+	}
+
+	for key, want := range tests {
+		if got := state.String(key); got != want {
+			t.Errorf("ParseVDF() %s = %q, want %q", key, got, want)
+		}
+	}
+
+	if got := state.Get("UserConfig").String("language"); got != "english" {
+		t.Errorf("ParseVDF() UserConfig.language = %q, want %q", got, "english")
+	}
+}
+
+func TestParseVDF_Unterminated(t *testing.T) {
+	_, err := ParseVDF(strings.NewReader(`"AppState" { "appid" "1"`))
+	if !errors.Is(err, UnexpectedEOFErr) {
+		t.Fatalf("ParseVDF() expected %v, received: %v", UnexpectedEOFErr, err)
+	}
+}
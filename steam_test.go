@@ -0,0 +1,121 @@
+package howlongtobeat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newSteamMockServer(t *testing.T) *mockServer {
+	t.Helper()
+
+	return newMockServer(t,
+		mockGame{game: GameDetailsGameDataGame{GameID: 10270, GameName: "The Witcher 3: Wild Hunt", GameType: "game", ProfileSteam: 292030}},
+		mockGame{game: GameDetailsGameDataGame{GameID: 10271, GameName: "The Witcher 3: Wild Hunt - Complete Edition", GameType: "game", ProfileSteamAlt: 499450}},
+	)
+}
+
+func Test_FindBySteamID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newSteamMockServer(t)
+	mockClient := server.client(t)
+
+	game, err := mockClient.FindBySteamID(ctx, 292030, "The Witcher 3")
+	if err != nil {
+		t.Fatalf("FindBySteamID() error = %v", err)
+	}
+
+	if game.GameID != 10270 {
+		t.Errorf("FindBySteamID() gameID = %v, want %v", game.GameID, 10270)
+	}
+
+	if calls := server.detailCalls.Load(); calls != 0 {
+		t.Errorf("FindBySteamID() fetched %d details, want none", calls)
+	}
+
+	if _, err = mockClient.FindBySteamID(ctx, 292030, "The Witcher 3"); err != nil {
+		t.Fatalf("FindBySteamID() error = %v", err)
+	}
+
+	if calls := server.searchCalls.Load(); calls != 1 {
+		t.Errorf("FindBySteamID() searched %d times, want cached result", calls)
+	}
+}
+
+func Test_FindBySteamID_AltID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newSteamMockServer(t)
+	mockClient := server.client(t)
+
+	game, err := mockClient.FindBySteamID(ctx, 499450, "The Witcher 3")
+	if err != nil {
+		t.Fatalf("FindBySteamID() error = %v", err)
+	}
+
+	if game.GameID != 10271 {
+		t.Errorf("FindBySteamID() gameID = %v, want %v", game.GameID, 10271)
+	}
+
+	if calls := server.detailCalls.Load(); calls == 0 {
+		t.Errorf("FindBySteamID() did not verify the candidates via Detail")
+	}
+}
+
+func Test_FindBySteamID_DetailFailed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := newSteamMockServer(t)
+	mockClient := server.client(t)
+
+	// The first candidate is unknown to the mock server, so its details fail with 404.
+	candidates := []SearchGameData{{GameID: 1, GameName: "The Witcher 3"}, {GameID: 10271, GameName: "The Witcher 3: Wild Hunt - Complete Edition"}}
+	server.searchHandler = func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(SearchGame{Count: len(candidates), Data: candidates})
+	}
+
+	game, err := mockClient.FindBySteamID(ctx, 499450, "The Witcher 3")
+	if err != nil {
+		t.Fatalf("FindBySteamID() error = %v", err)
+	}
+
+	if game.GameID != 10271 {
+		t.Errorf("FindBySteamID() gameID = %v, want %v", game.GameID, 10271)
+	}
+
+	// Without any verified candidate, the failure is returned instead of SteamGameNotFoundErr.
+	candidates = candidates[:1]
+
+	var statusErr *StatusError
+	if _, err = mockClient.FindBySteamID(ctx, 1, "Unknown"); !errors.As(err, &statusErr) || errors.Is(err, SteamGameNotFoundErr) {
+		t.Fatalf("FindBySteamID() expected a *StatusError, but received: %v", err)
+	}
+}
+
+func Test_FindBySteamID_NotFound(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mockClient := newSteamMockServer(t).client(t)
+
+	_, err := mockClient.FindBySteamID(ctx, 1, "The Witcher 3")
+	if !errors.Is(err, SteamGameNotFoundErr) {
+		t.Fatalf(`FindBySteamID() expected "%v" error, but received: %v`, SteamGameNotFoundErr, err)
+	}
+}
+
+func Test_FindBySteamID_InvalidAppID(t *testing.T) {
+	mockClient := &Client{}
+
+	_, err := mockClient.FindBySteamID(context.Background(), 0, "The Witcher 3")
+	if !errors.Is(err, SteamAppIDRequiredErr) {
+		t.Fatalf(`FindBySteamID() expected "%v" error, but received: %v`, SteamAppIDRequiredErr, err)
+	}
+}
@@ -0,0 +1,46 @@
+package howlongtobeat
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout sets the timeout of every outgoing request, including reading the response body.
+// A timeout of 0 keeps the default of 30 seconds, or the timeout of a client set with WithHTTPClient.
+// The timeout is set on a copy of the HTTP client, so a client set with WithHTTPClient is not modified and the order
+// of both options does not matter.
+func WithTimeout(timeout time.Duration) Option {
+	return func(client *Client) {
+		if timeout > 0 {
+			client.timeout = timeout
+		}
+	}
+}
+
+// WithOperationTimeout sets the timeout of the requests of an operation, e.g. a shorter timeout for OperationToken
+// than for OperationDetail. OperationDiscovery limits the whole discovery of the search endpoint, including all
+// scripts of the homepage. The timeout applies in addition to WithTimeout and the deadline of the context, the
+// shortest one wins. A timeout of 0 removes the timeout of the operation.
+func WithOperationTimeout(op Operation, timeout time.Duration) Option {
+	return func(client *Client) {
+		if client.operationTimeouts == nil {
+			client.operationTimeouts = make(map[Operation]time.Duration)
+		}
+
+		if timeout <= 0 {
+			delete(client.operationTimeouts, op)
+			return
+		}
+
+		client.operationTimeouts[op] = timeout
+	}
+}
+
+// operationContext applies the timeout of the operation set with WithOperationTimeout to ctx.
+func (c *Client) operationContext(ctx context.Context, op Operation) (context.Context, context.CancelFunc) {
+	if timeout := c.operationTimeouts[op]; timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	return ctx, func() {}
+}
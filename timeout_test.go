@@ -0,0 +1,70 @@
+package howlongtobeat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_WithTimeout_KeepsHTTPClient(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Minute}
+
+	mockClient, err := New(WithTimeout(5*time.Second), WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	if mockClient.client.Timeout != 5*time.Second || httpClient.Timeout != time.Minute {
+		t.Fatalf("WithTimeout() timeout = %v, provided client timeout = %v", mockClient.client.Timeout, httpClient.Timeout)
+	}
+
+	// Without a timeout, the timeout of the provided client is kept.
+	if mockClient, err = New(WithHTTPClient(httpClient), WithTimeout(0)); err != nil || mockClient.client != httpClient {
+		t.Fatalf("New() = %v, %v, want the provided client", mockClient, err)
+	}
+}
+
+func Test_WithOperationTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	mockClient, err := New(
+		WithBaseURL(server.URL),
+		WithOperationTimeout(OperationDetail, 20*time.Millisecond),
+		WithOperationTimeout(OperationToken, time.Second),
+		WithOperationTimeout(OperationToken, 0),
+	)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	start := time.Now()
+
+	if _, err = mockClient.Detail(ctx, 10270); !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 150*time.Millisecond {
+		t.Fatalf(`Detail() expected "%v" error within the operation timeout, but received: %v after %v`, context.DeadlineExceeded, err, time.Since(start))
+	}
+
+	if _, ok := mockClient.operationTimeouts[OperationToken]; ok {
+		t.Errorf("WithOperationTimeout() with 0 did not remove the timeout")
+	}
+
+	// Other operations are not limited.
+	var statusErr *StatusError
+	if err = mockClient.fetchToken(ctx, &ApiData{}); !errors.As(err, &statusErr) {
+		t.Errorf("fetchToken() expected a StatusError, but received: %v", err)
+	}
+}
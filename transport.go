@@ -0,0 +1,128 @@
+package howlongtobeat
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig tunes the HTTP transport of the Client. Zero values keep the defaults of the library, so only the
+// settings to change need to be set.
+type TransportConfig struct {
+	// MaxIdleConns is the maximum number of idle connections. The default is 10.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle connections per host. The default is 2.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the number of connections per host, including connections in use. The default is no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is the time an idle connection is kept open. The default is 15 seconds.
+	IdleConnTimeout time.Duration
+	// ResponseHeaderTimeout is the time to wait for the response headers after sending a request.
+	// The default is 15 seconds.
+	ResponseHeaderTimeout time.Duration
+	// TLSHandshakeTimeout is the time to wait for a TLS handshake. The default is no limit.
+	TLSHandshakeTimeout time.Duration
+	// DialTimeout is the time to wait for a connection to be established. The default is no limit.
+	DialTimeout time.Duration
+	// KeepAlive is the interval of TCP keep-alive probes. The default is 15 seconds, a negative value disables them.
+	KeepAlive time.Duration
+	// DisableKeepAlives opens a new connection for every request.
+	DisableKeepAlives bool
+	// DisableCompression disables the transparent gzip compression of responses.
+	DisableCompression bool
+	// DisableHTTP2 uses HTTP/1.1 only.
+	DisableHTTP2 bool
+}
+
+// TransportConfigErr is returned by New if WithTransportConfig is used with an HTTP client that does not use an
+// *http.Transport.
+var TransportConfigErr = errors.New("transport config requires an *http.Transport")
+
+// WithTransportConfig tunes the HTTP transport, e.g. the connection pool size per host for batch jobs.
+// The settings are applied on top of the defaults, or on top of the transport of a client set with WithHTTPClient.
+// The transport is changed on a copy, the transport of a client set with WithHTTPClient is not modified.
+func WithTransportConfig(config TransportConfig) Option {
+	return func(client *Client) {
+		client.transportConfig = &config
+	}
+}
+
+// defaultTransport returns the transport of a Client without WithHTTPClient.
+func defaultTransport() *http.Transport {
+	return &http.Transport{
+		MaxIdleConns:          10,
+		IdleConnTimeout:       15 * time.Second,
+		ResponseHeaderTimeout: 15 * time.Second,
+		DisableKeepAlives:     false,
+		ForceAttemptHTTP2:     true,
+	}
+}
+
+// setupTransport applies the transport config to a copy of the transport of the HTTP client.
+func (c *Client) setupTransport() error {
+	transport := http.DefaultTransport
+	if c.client.Transport != nil {
+		transport = c.client.Transport
+	}
+
+	httpTransport, ok := transport.(*http.Transport)
+	if !ok {
+		return TransportConfigErr
+	}
+
+	httpTransport = httpTransport.Clone()
+	c.transportConfig.apply(httpTransport)
+
+	httpClient := *c.client
+	httpClient.Transport = httpTransport
+	c.client = &httpClient
+
+	return nil
+}
+
+func (t *TransportConfig) apply(transport *http.Transport) {
+	if t.MaxIdleConns > 0 {
+		transport.MaxIdleConns = t.MaxIdleConns
+	}
+
+	if t.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+	}
+
+	if t.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = t.MaxConnsPerHost
+	}
+
+	if t.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = t.IdleConnTimeout
+	}
+
+	if t.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = t.ResponseHeaderTimeout
+	}
+
+	if t.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = t.TLSHandshakeTimeout
+	}
+
+	if t.DialTimeout != 0 || t.KeepAlive != 0 {
+		dialer := &net.Dialer{Timeout: t.DialTimeout, KeepAlive: t.KeepAlive}
+		transport.DialContext = dialer.DialContext
+	}
+
+	if t.DisableKeepAlives {
+		transport.DisableKeepAlives = true
+	}
+
+	if t.DisableCompression {
+		transport.DisableCompression = true
+	}
+
+	if t.DisableHTTP2 {
+		// A non-nil, empty TLSNextProto disables HTTP/2.
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(authority string, c *tls.Conn) http.RoundTripper)
+	}
+}
@@ -0,0 +1,71 @@
+package howlongtobeat
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_WithTransportConfig(t *testing.T) {
+	mockClient, err := New(WithTransportConfig(TransportConfig{
+		MaxIdleConnsPerHost: 50,
+		MaxConnsPerHost:     100,
+		TLSHandshakeTimeout: 5 * time.Second,
+		DialTimeout:         2 * time.Second,
+		DisableCompression:  true,
+		DisableHTTP2:        true,
+	}))
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	transport := mockClient.client.Transport.(*http.Transport)
+
+	if transport.MaxIdleConnsPerHost != 50 || transport.MaxConnsPerHost != 100 || transport.TLSHandshakeTimeout != 5*time.Second ||
+		transport.DialContext == nil || !transport.DisableCompression {
+		t.Errorf("WithTransportConfig() did not apply the config: %+v", transport)
+	}
+
+	if transport.ForceAttemptHTTP2 || transport.TLSNextProto == nil {
+		t.Errorf("WithTransportConfig() did not disable HTTP/2")
+	}
+
+	// Settings left empty keep the defaults.
+	if defaults := defaultTransport(); transport.MaxIdleConns != defaults.MaxIdleConns || transport.IdleConnTimeout != defaults.IdleConnTimeout ||
+		transport.ResponseHeaderTimeout != defaults.ResponseHeaderTimeout {
+		t.Errorf("WithTransportConfig() changed the defaults: %+v", transport)
+	}
+
+	if mockClient.client.Timeout != defaultRequestTimeout {
+		t.Errorf("WithTransportConfig() timeout = %v, want %v", mockClient.client.Timeout, defaultRequestTimeout)
+	}
+}
+
+func Test_WithTransportConfig_HTTPClient(t *testing.T) {
+	custom := &http.Transport{MaxIdleConns: 3}
+
+	mockClient, err := New(
+		WithTransportConfig(TransportConfig{MaxIdleConnsPerHost: 3}),
+		WithHTTPClient(&http.Client{Transport: custom}),
+		WithProxyPool([]string{"http://proxy:3128"}, nil),
+	)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	transport := mockClient.client.Transport.(*http.Transport)
+
+	// The config and the proxy pool are layered on a copy of the provided transport.
+	if transport == custom || transport.MaxIdleConns != 3 || transport.MaxIdleConnsPerHost != 3 || transport.Proxy == nil {
+		t.Errorf("WithTransportConfig() transport = %+v", transport)
+	}
+
+	if custom.MaxIdleConnsPerHost != 0 {
+		t.Errorf("WithTransportConfig() modified the provided transport")
+	}
+
+	if _, err = New(WithHTTPClient(&http.Client{Transport: &rewriteTransport{}}), WithTransportConfig(TransportConfig{})); !errors.Is(err, TransportConfigErr) {
+		t.Errorf(`New() expected "%v" error, but received: %v`, TransportConfigErr, err)
+	}
+}